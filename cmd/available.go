@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strigo/downloader/progress"
 	"strigo/logging"
 	"strigo/repository"
 	"strigo/repository/version"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -20,6 +22,11 @@ type AvailableOutput struct {
 	Error         string                `json:"error,omitempty"`
 }
 
+func init() {
+	availableCmd.Flags().StringVar(&targetOS, "os", "", "List the versions built for this operating system (default: host, e.g. linux, darwin, windows)")
+	availableCmd.Flags().StringVar(&targetArch, "arch", "", "List the versions built for this architecture (default: host, e.g. amd64, arm64)")
+}
+
 // availableCmd represents the available command
 var availableCmd = &cobra.Command{
 	Use:   "available [type] <distribution> [version]",
@@ -29,7 +36,8 @@ Examples:
   strigo available                  # List all available SDK types
   strigo available jdk             # List all available JDK distributions
   strigo available jdk temurin     # List all Temurin JDK versions
-  strigo available jdk temurin 11  # List Temurin JDK versions containing "11"`,
+  strigo available jdk temurin 11  # List Temurin JDK versions containing "11"
+  strigo available jdk temurin --os darwin --arch arm64  # List Temurin JDK versions for Apple Silicon`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		// Simple validation - config is not loaded yet
 		if len(args) > 3 {
@@ -176,16 +184,15 @@ func handleFullCommand(sdkType, distribution, versionFilter string, output *Avai
 		return nil
 	}
 
-	// Get registry information
-	registry, exists := cfg.Registries[sdkRepo.Registry]
-	if !exists {
-		err := fmt.Errorf("registry %s not found in configuration", sdkRepo.Registry)
-		logging.LogError("❌ %v", err)
+	// Get registry information, in priority order for fallback chains
+	registries, err := cfg.RegistriesFor(sdkRepo)
+	if err != nil {
+		logging.LogError("❌ %v in configuration", err)
 		return nil
 	}
 
 	// Fetch available versions
-	versions, err := repository.FetchAvailableVersions(sdkRepo, registry, "", true, GetPatternsFilePath())
+	versions, err := repository.FetchFromRegistries(sdkRepo, registries, "", true, GetPatternsFilePath(), targetPlatform())
 	if err != nil {
 		logging.LogError("❌ %v", err)
 		return nil
@@ -227,6 +234,10 @@ func handleFullCommand(sdkType, distribution, versionFilter string, output *Avai
 
 		// If no version matches the filter, display available versions
 		if len(filteredVersions) == 0 {
+			if jsonOutput {
+				output.Error = fmt.Sprintf("no version found matching major version %s", versionFilter)
+				return OutputJSON(output)
+			}
 			logging.LogOutput("❌ No version found matching major version %s", versionFilter)
 			logging.LogOutput("")
 			logging.LogOutput("💡 Available major versions are: %s", joinInts(availableMajors))
@@ -239,11 +250,15 @@ func handleFullCommand(sdkType, distribution, versionFilter string, output *Avai
 
 	// Sort versions
 	sort.Slice(versions, func(i, j int) bool {
-		return repository.CompareVersions(versions[i].Version, versions[j].Version)
+		return version.CompareVersions(versions[i].Name(), versions[j].Name())
 	})
 
 	output.Versions = versions
 
+	if jsonOutput {
+		return OutputJSON(output)
+	}
+
 	displayVersions(versions, sdkType, distribution)
 	return nil
 }
@@ -261,8 +276,9 @@ func displayVersions(versions []repository.SDKAsset, sdkType, distribution strin
 	logging.LogDebug("Processing %d versions for display", len(versions))
 
 	// Group versions by major version
-	versionGroups := make(map[string][]string)
+	versionGroups := make(map[string][]repository.SDKAsset)
 	allMajorVersions := make(map[string]bool)
+	ltsMajors := make(map[string]bool)
 
 	// Retrieve all available major versions
 	for _, asset := range versions {
@@ -271,7 +287,10 @@ func displayVersions(versions []repository.SDKAsset, sdkType, distribution strin
 		logging.LogDebug("  Extracted major version: %s", majorVersion)
 		if majorVersion != "" {
 			allMajorVersions[majorVersion] = true
-			versionGroups[majorVersion] = append(versionGroups[majorVersion], asset.Version)
+			versionGroups[majorVersion] = append(versionGroups[majorVersion], asset)
+			if asset.LTS {
+				ltsMajors[majorVersion] = true
+			}
 			logging.LogDebug("  Added to version groups. Current groups: %v", versionGroups)
 		}
 	}
@@ -319,12 +338,17 @@ func displayVersions(versions []repository.SDKAsset, sdkType, distribution strin
 
 		// Sort versions in each group
 		sort.Slice(versions, func(i, j int) bool {
-			return repository.CompareVersions(versions[i], versions[j])
+			return version.CompareVersions(versions[i].Name(), versions[j].Name())
 		})
 
-		logging.LogOutput("-%d :", majorNum)
-		for _, version := range versions {
-			logging.LogOutput("    ✅ %s", version)
+		entry, known := getLifecycle().Lookup(sdkType, distribution, majorNum)
+		if label := lifecycleLabel(ltsMajors[major] || entry.LTS, entry, known); label != "" {
+			logging.LogOutput("-%d (%s) :", majorNum, label)
+		} else {
+			logging.LogOutput("-%d :", majorNum)
+		}
+		for _, asset := range versions {
+			logging.LogOutput("    ✅ %s", formatAssetLine(asset))
 		}
 		logging.LogOutput("") // Empty line between groups
 	}
@@ -332,3 +356,37 @@ func displayVersions(versions []repository.SDKAsset, sdkType, distribution strin
 	logging.LogOutput("💡 To install a specific version:")
 	logging.LogOutput(fmt.Sprintf("   strigo install %s %s [version]", sdkType, distribution))
 }
+
+// formatAssetLine renders a version with the size, modification date and content type reported by the registry
+func formatAssetLine(asset repository.SDKAsset) string {
+	var details []string
+	if asset.Size > 0 {
+		details = append(details, progress.FormatBytes(asset.Size))
+	}
+	if modified, err := time.Parse(time.RFC3339, asset.LastModified); err == nil {
+		details = append(details, modified.Format("2006-01-02"))
+	}
+	if asset.ContentType != "" {
+		details = append(details, asset.ContentType)
+	}
+	if len(details) == 0 {
+		return asset.Name()
+	}
+	return fmt.Sprintf("%-20s %s", asset.Name(), strings.Join(details, "  "))
+}
+
+// lifecycleLabel describes the LTS status and end of life of a major version: "LTS, EOL 2029-09-30"
+func lifecycleLabel(lts bool, entry repository.LifecycleEntry, known bool) string {
+	var parts []string
+	if lts {
+		parts = append(parts, "LTS")
+	}
+	if known && entry.EOL != "" {
+		if entry.IsEOL(time.Now()) {
+			parts = append(parts, "⚠️  EOL since "+entry.EOL)
+		} else {
+			parts = append(parts, "EOL "+entry.EOL)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
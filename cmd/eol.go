@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strigo/repository"
+	"strigo/repository/version"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var eolAll bool
+
+// Lifecycle statuses reported by the eol command
+const (
+	eolStatusSupported    = "supported"
+	eolStatusSupportEnded = "support ended"
+	eolStatusEOL          = "end of life"
+	eolStatusUnknown      = "unknown"
+)
+
+// EOLReportEntry is the lifecycle of one major version in the eol report
+type EOLReportEntry struct {
+	SDKType      string   `json:"sdk_type"`
+	Distribution string   `json:"distribution"`
+	Major        int      `json:"major"`
+	Installed    []string `json:"installed,omitempty"`
+	Status       string   `json:"status"`
+
+	repository.LifecycleEntry
+}
+
+var eolCmd = &cobra.Command{
+	Use:   "eol [type] [distribution]",
+	Short: "Report the LTS and end-of-life status of installed SDKs",
+	Long: `Report the LTS and end-of-life status of installed SDK versions, from the lifecycle
+data configured with lifecycle_file or lifecycle_url in [general]. For example:
+strigo eol              # All installed SDKs
+strigo eol jdk          # Installed JDKs
+strigo eol jdk temurin  # Installed Temurin JDKs
+strigo eol --all        # Every major version of the lifecycle data`,
+	Args: cobra.MaximumNArgs(2),
+	Run:  eol,
+}
+
+func init() {
+	eolCmd.Flags().BoolVar(&eolAll, "all", false, "Report every major version of the lifecycle data, installed or not")
+}
+
+func eol(cmd *cobra.Command, args []string) {
+	if err := handleEOL(args); err != nil {
+		ExitWithError(err)
+	}
+}
+
+func handleEOL(args []string) error {
+	lifecycle := getLifecycle()
+	if lifecycle == nil {
+		return fmt.Errorf("no lifecycle data available, set lifecycle_file or lifecycle_url in [general]")
+	}
+
+	var sdkType, distribution string
+	if len(args) > 0 {
+		sdkType = args[0]
+		if _, exists := cfg.SDKTypes[sdkType]; !exists {
+			return fmt.Errorf("SDK type %s not found in configuration", sdkType)
+		}
+	}
+	if len(args) > 1 {
+		distribution = args[1]
+	}
+
+	report, err := buildEOLReport(lifecycle, sdkType, distribution, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		return OutputJSON(report)
+	}
+
+	if len(report) == 0 {
+		fmt.Printf("No installed SDKs found\n")
+		return nil
+	}
+
+	fmt.Printf("%-6s %-14s %-6s %-4s %-11s %-11s %-14s %s\n", "TYPE", "DISTRIBUTION", "MAJOR", "LTS", "SUPPORT", "EOL", "STATUS", "INSTALLED")
+	fmt.Println(strings.Repeat("─", 90))
+	for _, entry := range report {
+		lts := ""
+		if entry.LTS {
+			lts = "yes"
+		}
+		status := entry.Status
+		if status == eolStatusEOL {
+			status = "⚠️  " + status
+		}
+		fmt.Printf("%-6s %-14s %-6d %-4s %-11s %-11s %-14s %s\n",
+			entry.SDKType, entry.Distribution, entry.Major, lts,
+			orDash(entry.SupportEnd), orDash(entry.EOL), status, strings.Join(entry.Installed, ", "))
+	}
+	return nil
+}
+
+// buildEOLReport returns the lifecycle of the installed major versions, or with eolAll of
+// every major version of the lifecycle data, optionally restricted to a type and distribution
+func buildEOLReport(lifecycle repository.Lifecycle, sdkType, distribution string, now time.Time) ([]EOLReportEntry, error) {
+	type key struct {
+		sdkType, distribution string
+		major                 int
+	}
+	installed := make(map[key][]string)
+
+	for typeName, typeConfig := range cfg.SDKTypes {
+		if sdkType != "" && typeName != sdkType {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(cfg.General.SDKInstallDir, typeConfig.InstallDir))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read distributions directory: %w", err)
+		}
+		for _, dist := range entries {
+			if !dist.IsDir() || strings.HasPrefix(dist.Name(), ".") || (distribution != "" && dist.Name() != distribution) {
+				continue
+			}
+			versions, err := installedVersions(cfg, typeName, dist.Name())
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range versions {
+				parsed, err := version.Parse(v)
+				if err != nil {
+					continue
+				}
+				k := key{typeName, dist.Name(), parsed.Major()}
+				installed[k] = append(installed[k], v)
+			}
+		}
+	}
+
+	if eolAll {
+		for typeName, distributions := range lifecycle {
+			if sdkType != "" && typeName != sdkType {
+				continue
+			}
+			for dist, majors := range distributions {
+				if distribution != "" && dist != distribution {
+					continue
+				}
+				for major := range majors {
+					if n, err := strconv.Atoi(major); err == nil {
+						k := key{typeName, dist, n}
+						if _, exists := installed[k]; !exists {
+							installed[k] = nil
+						}
+					}
+				}
+			}
+		}
+	}
+
+	var report []EOLReportEntry
+	for k, versions := range installed {
+		sort.Slice(versions, func(i, j int) bool {
+			return version.CompareVersions(versions[i], versions[j])
+		})
+		entry := EOLReportEntry{SDKType: k.sdkType, Distribution: k.distribution, Major: k.major, Installed: versions, Status: eolStatusUnknown}
+		if lc, ok := lifecycle.Lookup(k.sdkType, k.distribution, k.major); ok {
+			entry.LifecycleEntry = lc
+			entry.Status = lifecycleStatus(lc, now)
+		}
+		report = append(report, entry)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if a.SDKType != b.SDKType {
+			return a.SDKType < b.SDKType
+		}
+		if a.Distribution != b.Distribution {
+			return a.Distribution < b.Distribution
+		}
+		return a.Major < b.Major
+	})
+	return report, nil
+}
+
+// lifecycleStatus returns the support status of a lifecycle entry at now
+func lifecycleStatus(entry repository.LifecycleEntry, now time.Time) string {
+	if entry.IsEOL(now) {
+		return eolStatusEOL
+	}
+	if entry.IsSupportEnded(now) {
+		return eolStatusSupportEnded
+	}
+	if entry.EOL == "" && entry.SupportEnd == "" {
+		return eolStatusUnknown
+	}
+	return eolStatusSupported
+}
+
+// orDash returns s, or "-" when empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -1,14 +1,23 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strigo/config"
 	"strigo/downloader"
 	"strigo/downloader/core"
 	"strigo/downloader/jdk"
+	"strigo/downloader/network"
 	"strigo/logging"
 	"strigo/repository"
+	versionpkg "strigo/repository/version"
+	"syscall"
 
 	"github.com/spf13/cobra"
 )
@@ -17,12 +26,16 @@ var (
 	jdkCacertsPath     string
 	jdkCacertsPassword string
 	nodeExtraCaCerts   string
+	skipVerify         bool
 )
 
 func init() {
 	installCmd.Flags().StringVar(&jdkCacertsPath, "jdk-cacerts-path", "", "Override cacerts path in JDK (e.g., 'jre/lib/security/cacerts' for Java 8)")
 	installCmd.Flags().StringVar(&jdkCacertsPassword, "jdk-cacerts-password", "", "Override cacerts password (default: 'changeit', use '' for password-less PKCS12)")
 	installCmd.Flags().StringVar(&nodeExtraCaCerts, "node-extra-ca-certs", "", "Path to PEM bundle for Node.js extra CA certificates (supports multiple certificates)")
+	installCmd.Flags().BoolVar(&skipVerify, "skip-verify", false, "Skip checksum verification of the downloaded archive (not recommended)")
+	installCmd.Flags().StringVar(&targetOS, "os", "", "Install the SDK built for this operating system (default: host, e.g. linux, darwin, windows)")
+	installCmd.Flags().StringVar(&targetArch, "arch", "", "Install the SDK built for this architecture (default: host, e.g. amd64, arm64)")
 }
 
 var installCmd = &cobra.Command{
@@ -31,6 +44,15 @@ var installCmd = &cobra.Command{
 	Long: `Install a specific SDK version. For example:
 	strigo install jdk temurin 11.0.24_8
 	strigo install jdk corretto 8u442b06
+	strigo install jdk zulu 21.0.5-crac
+	strigo install jdk temurin 21 --os linux --arch arm64
+
+The version may also be a constraint, resolved to the newest matching release:
+	latest        newest release
+	lts           newest release of a long-term support line
+	17            newest 17 release
+	17.0.x        newest 17.0 release
+	">=17 <21"    newest release in the range
 
 Available SDK types:
 	jdk     Java Development Kit
@@ -57,6 +79,9 @@ Available distributions for jdk:
   # Install Corretto JDK 8
   strigo install jdk corretto 8u442b06
 
+  # Install the newest Temurin JDK 21
+  strigo install jdk temurin 21
+
   # To see available versions:
   strigo available jdk temurin`,
 }
@@ -68,6 +93,10 @@ func install(cmd *cobra.Command, args []string) {
 
 	if err := handleInstall(sdkType, distribution, version); err != nil {
 		logging.LogError("❌ Error executing command: %v", err)
+		if errors.Is(err, context.Canceled) {
+			// Interrupted, the staging directory has been removed by now
+			os.Exit(130)
+		}
 		return
 	}
 }
@@ -95,27 +124,56 @@ func handleInstall(sdkType, distribution, version string) error {
 		return fmt.Errorf("distribution %s is not of type %s", distribution, sdkType)
 	}
 
-	// Get registry information
-	registry, exists := cfg.Registries[sdkRepo.Registry]
-	if !exists {
-		logging.LogError("❌ Registry %s not found in configuration", sdkRepo.Registry)
-		return fmt.Errorf("registry %s not found", sdkRepo.Registry)
+	// Get registry information, in priority order for fallback chains
+	registries, err := cfg.RegistriesFor(sdkRepo)
+	if err != nil {
+		logging.LogError("❌ %v in configuration", err)
+		return err
 	}
 
-	// Fetch available versions with filter
-	assets, err := repository.FetchAvailableVersions(sdkRepo, registry, version, true, GetPatternsFilePath()) // true to remove display
+	// Versions may be given as a constraint or alias ("latest", "17", "17.0.x", ">=17 <21", "lts").
+	// Constraints restricted to a major version only fetch that line; anything else is looked up as is.
+	requested := version
+	constraint, constraintErr := versionpkg.ParseConstraint(version)
+	filter := version
+	if constraintErr == nil {
+		filter = ""
+		if major := constraint.Major(); major > 0 {
+			filter = strconv.Itoa(major)
+		}
+	}
+	platform := targetPlatform()
+	if platform != repository.HostPlatform() {
+		logging.LogInfo("ℹ️  Installing the %s build, which may not run on this machine", platform)
+	}
+	assets, err := repository.FetchFromRegistries(sdkRepo, registries, filter, true, GetPatternsFilePath(), platform) // true to remove display
 	if err != nil {
 		logging.LogError("❌ Failed to fetch versions: %v", err)
 		return fmt.Errorf("failed to fetch versions: %w", err)
 	}
 
-	// Find exact version match
 	var matchedAsset *repository.SDKAsset
-	for i := range assets {
-		if assets[i].Version == version {
-			matchedAsset = &assets[i]
-			break
+	if constraintErr != nil || constraint.IsExact() {
+		matchedAsset = findVersion(assets, version)
+	} else {
+		// Variant builds (crac, fx, musl...) are only installed when requested by name
+		var candidates []string
+		ltsVersions := make(map[string]bool)
+		for _, asset := range assets {
+			if asset.Variant != "" {
+				continue
+			}
+			candidates = append(candidates, asset.Version)
+			ltsVersions[asset.Version] = asset.LTS
 		}
+		resolved, err := resolveConstraint(constraint, sdkType, distribution, candidates, ltsVersions)
+		if err != nil {
+			logging.LogError("❌ %v", err)
+			logging.LogInfo("💡 Use 'strigo available %s %s' to see available versions", sdkType, distribution)
+			return err
+		}
+		logging.LogInfo("🎯 Resolved %s to version %s", requested, resolved)
+		matchedAsset = findVersion(assets, resolved)
 	}
 
 	if matchedAsset == nil {
@@ -124,6 +182,8 @@ func handleInstall(sdkType, distribution, version string) error {
 		return fmt.Errorf("version %s not found", version)
 	}
 
+	// Install under the registry's spelling of the version, followed by the variant if any
+	version = matchedAsset.Name()
 	logging.LogInfo("✅ Found version %s, preparing for installation...", version)
 
 	// Get installation path
@@ -139,105 +199,111 @@ func handleInstall(sdkType, distribution, version string) error {
 		return fmt.Errorf("version %s is already installed", version)
 	}
 
-	// Create installation directory
-	if err := os.MkdirAll(filepath.Dir(installPath), 0755); err != nil {
-		logging.LogError("❌ Failed to create installation directory: %v", err)
-		return fmt.Errorf("failed to create installation directory: %w", err)
+	// Remove leftovers of interrupted installs before staging a new one
+	if _, err := downloader.CleanStaleStaging(filepath.Dir(installPath)); err != nil {
+		logging.LogDebug("⚠️  %v", err)
 	}
 
-	// Download and extract - create manager with auth if credentials are provided
-	var manager *downloader.Manager
-	if registry.Username != "" && registry.Password != "" {
-		logging.LogDebug("🔐 Creating download manager with authentication")
-		manager = downloader.NewManagerWithAuth(registry.Username, registry.Password)
-	} else {
-		manager = downloader.NewManager()
+	// Everything is prepared in a staging directory that is moved into place only on success,
+	// so a crash or Ctrl-C never leaves a half-installed SDK behind
+	staging, err := downloader.NewStaging(installPath)
+	if err != nil {
+		logging.LogError("❌ Failed to create installation directory: %v", err)
+		return fmt.Errorf("failed to create installation directory: %w", err)
 	}
+	committed := false
+	defer func() {
+		if !committed {
+			staging.Abort()
+		}
+	}()
+	ctx, stopSignals := interruptContext()
+	defer stopSignals()
+	stagingPath := staging.Path()
 
 	opts := core.DownloadOptions{
-		DownloadURL:  matchedAsset.DownloadUrl,
 		CacheDir:     cfg.General.CacheDir,
-		InstallPath:  installPath,
+		InstallPath:  stagingPath,
 		SDKType:      sdkType,
 		Distribution: distribution,
 		Version:      version,
 		KeepCache:    cfg.General.KeepCache,
-		Username:     registry.Username,
-		Password:     registry.Password,
+		SkipVerify:   skipVerify,
+
+		StripComponents: config.ResolveStripComponents(sdkTypeConfig, sdkRepo),
+
+		Binary:     sdkTypeConfig.IsBinary(),
+		BinaryName: binaryName(sdkRepo, distribution),
 	}
-	err = manager.DownloadAndExtract(opts)
 
-	if err != nil {
+	// Download from the registry that supplied the version, falling over to its mirrors
+	// when a registry is unreachable or serves a corrupted file
+	sources := append([]repository.SDKAsset{*matchedAsset}, matchedAsset.Mirrors...)
+	var result *core.DownloadResult
+	for i := range sources {
+		// Files without platform, such as multi-platform OCI images, are resolved for the target platform
+		if sources[i].OS == "" && sources[i].Arch == "" {
+			sources[i].OS, sources[i].Arch = platform.OS, platform.Arch
+		}
+		result, err = downloadAsset(ctx, &sources[i], opts)
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			logging.LogError("❌ Installation interrupted, cleaning up")
+			return fmt.Errorf("installation interrupted: %w", ctx.Err())
+		}
+		if i+1 < len(sources) && downloader.IsMirrorFailure(err) {
+			logging.LogInfo("⚠️  Download from %s failed (%v), trying %s", sources[i].Registry, err, sources[i+1].Registry)
+			continue
+		}
 		logging.LogError("❌ Installation failed: %v", err)
-		// Cleanup on failure
-		os.RemoveAll(installPath)
 		return fmt.Errorf("installation failed: %w", err)
 	}
 
-	// For JDKs, inject custom certificates if configured
-	if sdkType == "jdk" && len(cfg.General.CustomCertificates) > 0 {
-		// Find the extracted JDK folder
-		entries, err := os.ReadDir(installPath)
+	// Locate the SDK home inside the installation, binaries live in <install>/bin
+	homePath := "."
+	if !sdkTypeConfig.IsBinary() {
+		homePath, err = downloader.FindHome(stagingPath)
 		if err != nil {
-			return fmt.Errorf("failed to read installation directory: %w", err)
+			return err
 		}
+	}
+	logging.LogDebug("🏠 SDK home: %s", homePath)
 
-		// JDK directory selection logic
-		var jdkDir string
-		dirCount := 0
-
-		// Count directories and remember the first one
-		for _, entry := range entries {
-			if entry.IsDir() {
-				dirCount++
-				// If it's the first directory, remember it
-				if jdkDir == "" {
-					jdkDir = entry.Name()
-				}
-			}
-		}
+	// For JDKs, inject custom certificates if configured
+	if sdkType == "jdk" && len(cfg.General.CustomCertificates) > 0 {
+		jdkPath := filepath.Join(stagingPath, homePath)
 
-		// If multiple directories exist, it's ambiguous
-		if dirCount > 1 {
-			jdkDir = ""
+		// Determine path override (CLI takes precedence over config)
+		pathOverride := jdkCacertsPath
+		if pathOverride == "" {
+			pathOverride = cfg.General.JDKCacertsOverride
 		}
 
-		if jdkDir == "" {
-			logging.LogDebug("⚠️  Could not find JDK directory, skipping certificate injection")
-		} else {
-			// Use the full path for the JDK root
-			jdkPath := filepath.Join(installPath, jdkDir)
-
-			// Determine path override (CLI takes precedence over config)
-			pathOverride := jdkCacertsPath
-			if pathOverride == "" {
-				pathOverride = cfg.General.JDKCacertsOverride
-			}
+		// Determine password (CLI takes precedence over config, default to "changeit")
+		password := jdkCacertsPassword
+		if password == "" {
+			password = cfg.General.JDKCacertsPassword
+		}
+		if password == "" {
+			password = "changeit"
+		}
 
-			// Determine password (CLI takes precedence over config, default to "changeit")
-			password := jdkCacertsPassword
-			if password == "" {
-				password = cfg.General.JDKCacertsPassword
-			}
-			if password == "" {
-				password = "changeit"
-			}
+		// Create certificate manager and inject certificates
+		certManager := jdk.NewCertificateManager()
+		err := certManager.InjectCertificates(
+			jdkPath,
+			cfg.General.CustomCertificates,
+			pathOverride,
+			password,
+		)
 
-			// Create certificate manager and inject certificates
-			certManager := jdk.NewCertificateManager()
-			err := certManager.InjectCertificates(
-				jdkPath,
-				cfg.General.CustomCertificates,
-				pathOverride,
-				password,
-			)
-
-			if err != nil {
-				// Non-fatal: log warning but continue installation
-				logging.LogDebug("⚠️  Certificate injection failed: %v", err)
-				logging.LogInfo("ℹ️  JDK installation is complete but custom certificates were not injected")
-				logging.LogInfo("💡 You can manually add certificates using Java's keytool if needed")
-			}
+		if err != nil {
+			// Non-fatal: log warning but continue installation
+			logging.LogDebug("⚠️  Certificate injection failed: %v", err)
+			logging.LogInfo("ℹ️  JDK installation is complete but custom certificates were not injected")
+			logging.LogInfo("💡 You can manually add certificates using Java's keytool if needed")
 		}
 	} else if sdkType == "jdk" {
 		logging.LogDebug("📋 No custom certificates configured, JDK will use default certificate store")
@@ -259,6 +325,22 @@ func handleInstall(sdkType, distribution, version string) error {
 		SDKType:      sdkType,
 		Distribution: distribution,
 		Version:      version,
+		Variant:      matchedAsset.Variant,
+
+		Checksum:          result.Checksum,
+		ChecksumAlgorithm: result.ChecksumAlgorithm,
+		ChecksumVerified:  result.Verified,
+
+		HomePath: homePath,
+	}
+	if requested != version {
+		metadata.Requested = requested
+	}
+	if platform != repository.HostPlatform() {
+		metadata.Platform = platform.String()
+	}
+	if sdkTypeConfig.IsBinary() {
+		metadata.Artifact = config.ArtifactBinary
 	}
 
 	// Add Node.js specific metadata if provided
@@ -281,14 +363,112 @@ func handleInstall(sdkType, distribution, version string) error {
 		}
 	}
 
-	if err := downloader.SaveMetadata(installPath, metadata); err != nil {
+	if err := downloader.SaveMetadata(stagingPath, metadata); err != nil {
 		logging.LogDebug("⚠️  Failed to save installation metadata: %v", err)
 		// Non-fatal, continue
 	}
 
+	// Move the finished installation into place, unless interrupted meanwhile
+	if ctx.Err() != nil {
+		logging.LogError("❌ Installation interrupted, cleaning up")
+		return fmt.Errorf("installation interrupted: %w", ctx.Err())
+	}
+	if err := staging.Commit(); err != nil {
+		logging.LogError("❌ Failed to finalize installation: %v", err)
+		return fmt.Errorf("failed to finalize installation: %w", err)
+	}
+	committed = true
+
 	logging.LogInfo("✅ Successfully installed %s %s version %s", sdkType, distribution, version)
 	logging.LogInfo("📂 Installation path: %s", installPath)
 	logging.LogInfo("ℹ️  To set this version as active, run: strigo use %s %s %s", sdkType, distribution, version)
 
 	return nil
 }
+
+// downloadAsset downloads and extracts an asset with the credentials and network settings
+// of the registry that supplied it
+func downloadAsset(ctx context.Context, asset *repository.SDKAsset, opts core.DownloadOptions) (*core.DownloadResult, error) {
+	registry, exists := cfg.Registries[asset.Registry]
+	if !exists {
+		return nil, fmt.Errorf("registry %s not found", asset.Registry)
+	}
+
+	// OCI artifacts are located through the manifest of the selected tag
+	if err := repository.ResolveManifest(asset, registry); err != nil {
+		return nil, fmt.Errorf("failed to resolve artifact: %w", err)
+	}
+
+	// Registries publishing checksums separately (Maven sidecars, Disco package info) are only queried for the selected version
+	if !opts.SkipVerify {
+		if err := repository.ResolveChecksum(asset, registry); err != nil {
+			return nil, fmt.Errorf("failed to fetch checksum: %w", err)
+		}
+	}
+
+	// Create manager with the registry credentials and network settings
+	settings, err := network.SettingsFromRegistry(registry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network settings for registry %s: %w", asset.Registry, err)
+	}
+	if asset.BearerToken != "" {
+		if settings.Headers == nil {
+			settings.Headers = http.Header{}
+		}
+		settings.Headers.Set("Authorization", "Bearer "+asset.BearerToken)
+	}
+	if registry.Username != "" && registry.Password != "" {
+		logging.LogDebug("🔐 Creating download manager with authentication")
+	}
+	manager := downloader.NewManagerWithSettings(registry.Username, registry.Password, settings)
+	manager.SetProgressReporter(newProgressReporter())
+	manager.SetContext(ctx)
+
+	logging.LogDebug("📡 Downloading from registry %s", asset.Registry)
+	opts.DownloadURL = asset.DownloadUrl
+	opts.FileName = asset.ArchiveName
+	opts.Size = asset.Size
+	opts.Username = registry.Username
+	opts.Password = registry.Password
+	opts.Checksum = asset.Checksum
+	opts.ChecksumAlgorithm = asset.ChecksumAlgorithm
+	return manager.DownloadAndExtract(opts)
+}
+
+// binaryName returns the executable name of a binary artifact distribution
+func binaryName(sdkRepo config.SDKRepository, distribution string) string {
+	if sdkRepo.BinaryName != "" {
+		return sdkRepo.BinaryName
+	}
+	return distribution
+}
+
+// interruptContext returns a context cancelled by Ctrl-C or SIGTERM, which stops the download
+// and extraction so the install returns and removes its staging directory. A second signal
+// terminates the process as usual. The returned function stops listening for signals.
+func interruptContext() (context.Context, func()) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	context.AfterFunc(ctx, stop)
+	return ctx, stop
+}
+
+// findVersion returns the asset whose name is exactly the requested one, or else the one
+// parsing to the same version, or nil. Variant builds are requested by name ("21.0.5-crac").
+func findVersion(assets []repository.SDKAsset, requested string) *repository.SDKAsset {
+	for i := range assets {
+		if assets[i].Name() == requested {
+			return &assets[i]
+		}
+	}
+
+	want, err := versionpkg.Parse(requested)
+	if err != nil {
+		return nil
+	}
+	for i := range assets {
+		if v, err := versionpkg.Parse(assets[i].Name()); err == nil && v.Compare(want) == 0 {
+			return &assets[i]
+		}
+	}
+	return nil
+}
@@ -21,6 +21,7 @@ type CommandOutput struct {
 	Types         []string `json:"types,omitempty"`
 	Distributions []string `json:"distributions,omitempty"`
 	Versions      []string `json:"versions,omitempty"`
+	Warnings      []string `json:"warnings,omitempty"`
 	Error         string   `json:"error,omitempty"`
 }
 
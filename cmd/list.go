@@ -8,6 +8,9 @@ import (
 	"strconv"
 	"strigo/config"
 	"strigo/repository"
+	"strigo/repository/version"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -112,7 +115,7 @@ func listDistributions(cfg *config.Config, sdkType string, output *CommandOutput
 
 	var dists []string
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
 			dists = append(dists, entry.Name())
 		}
 	}
@@ -162,16 +165,18 @@ func listVersions(cfg *config.Config, sdkType, distribution string, output *Comm
 
 	var versions []string
 	for _, entry := range entries {
-		if entry.IsDir() {
+		// Skip hidden entries such as staging directories of in-progress installs
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
 			versions = append(versions, entry.Name())
 		}
 	}
 
 	// Sort versions
 	sort.Slice(versions, func(i, j int) bool {
-		return repository.CompareVersions(versions[i], versions[j])
+		return version.CompareVersions(versions[i], versions[j])
 	})
 	output.Versions = versions
+	output.Warnings = eolWarnings(sdkType, distribution, versions)
 
 	if jsonOutput {
 		return OutputJSON(output)
@@ -184,9 +189,9 @@ func listVersions(cfg *config.Config, sdkType, distribution string, output *Comm
 
 	// Group versions by major version
 	versionGroups := make(map[string][]string)
-	for _, version := range versions {
-		majorVersion := repository.ExtractMajorVersion(version)
-		versionGroups[majorVersion] = append(versionGroups[majorVersion], version)
+	for _, v := range versions {
+		majorVersion := repository.ExtractMajorVersion(v)
+		versionGroups[majorVersion] = append(versionGroups[majorVersion], v)
 	}
 
 	// Get sorted major versions
@@ -207,15 +212,44 @@ func listVersions(cfg *config.Config, sdkType, distribution string, output *Comm
 
 		// Sort versions in each group
 		sort.Slice(versions, func(i, j int) bool {
-			return repository.CompareVersions(versions[i], versions[j])
+			return version.CompareVersions(versions[i], versions[j])
 		})
 
 		fmt.Printf("-%s :\n", major)
-		for _, version := range versions {
-			fmt.Printf("    ✅ %s\n", version)
+		for _, v := range versions {
+			fmt.Printf("    ✅ %s\n", v)
 		}
 		fmt.Println()
 	}
 
+	for _, warning := range output.Warnings {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+	if len(output.Warnings) > 0 {
+		fmt.Printf("💡 Run 'strigo eol' for the lifecycle of installed SDKs\n")
+	}
+
 	return nil
 }
+
+// eolWarnings returns a warning for each installed major version past its end of life
+func eolWarnings(sdkType, distribution string, versions []string) []string {
+	lifecycle := getLifecycle()
+	if lifecycle == nil {
+		return nil
+	}
+
+	var warnings []string
+	seen := make(map[int]bool)
+	for _, v := range versions {
+		parsed, err := version.Parse(v)
+		if err != nil || seen[parsed.Major()] {
+			continue
+		}
+		seen[parsed.Major()] = true
+		if entry, ok := lifecycle.Lookup(sdkType, distribution, parsed.Major()); ok && entry.IsEOL(time.Now()) {
+			warnings = append(warnings, fmt.Sprintf("%s %s %d reached end of life on %s", sdkType, distribution, parsed.Major(), entry.EOL))
+		}
+	}
+	return warnings
+}
@@ -68,6 +68,7 @@ func init() {
 	rootCmd.AddCommand(useCmd)
 	rootCmd.AddCommand(cleanCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(eolCmd)
 
 	// Allow flags to be placed after arguments
 	rootCmd.Flags().SetInterspersed(true)
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strigo/config"
 	"strigo/downloader"
 	"strigo/logging"
+	"strigo/repository/version"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -44,8 +46,10 @@ var useCmd = &cobra.Command{
 	Short: "Set a specific SDK version as active",
 	Long: `Set a specific SDK version as active. For example:
 strigo use jdk temurin 11.0.24_8
+strigo use jdk temurin 21       # Newest installed 21 release
 
-This will create a symbolic link to the specified version.`,
+This will create a symbolic link to the specified version. Constraints such as
+"latest", "lts", "17.0.x" or ">=17 <21" select the newest matching installed version.`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if unsetEnv {
 			if len(args) != 1 || (args[0] != "jdk" && args[0] != "node") {
@@ -88,39 +92,6 @@ func use(cmd *cobra.Command, args []string) {
 	}
 }
 
-func getSDKBinPath(basePath string, sdkType string) (string, error) {
-	entries, err := os.ReadDir(basePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to read installation directory: %w", err)
-	}
-
-	// SDK directory selection logic
-	var sdkDir string
-	dirCount := 0
-	
-	// Count directories and remember the first one
-	for _, entry := range entries {
-		if entry.IsDir() {
-			dirCount++
-			// If it's the first directory, remember it
-			if sdkDir == "" {
-				sdkDir = entry.Name()
-			}
-		}
-	}
-	
-	// If multiple directories exist, it's ambiguous
-	if dirCount > 1 {
-		sdkDir = ""
-	}
-
-	if sdkDir == "" {
-		return "", fmt.Errorf("could not find %s directory in %s", strings.ToUpper(sdkType), basePath)
-	}
-
-	return filepath.Join(basePath, sdkDir), nil
-}
-
 func findRcFile() (string, error) {
 	// Check if shell_config_path is set in config
 	if cfg.General.ShellConfigPath != "" {
@@ -255,15 +226,32 @@ func handleUse(sdkType, distribution, version string) error {
 	// Build the installation path
 	installPath := filepath.Join(cfg.General.SDKInstallDir, sdkTypeConfig.InstallDir, distribution, version)
 
-	// Check if the SDK is installed
+	// Check if the SDK is installed, resolving constraints and aliases ("17", "latest", "lts")
+	// against the installed versions
 	if _, err := os.Stat(installPath); os.IsNotExist(err) {
-		return fmt.Errorf("version %s %s %s is not installed", sdkType, distribution, version)
+		resolved, resolveErr := resolveInstalled(sdkType, distribution, version)
+		if resolveErr != nil {
+			return fmt.Errorf("version %s %s %s is not installed: %w", sdkType, distribution, version, resolveErr)
+		}
+		logging.LogInfo("🎯 Resolved %s to installed version %s", version, resolved)
+		version = resolved
+		installPath = filepath.Join(cfg.General.SDKInstallDir, sdkTypeConfig.InstallDir, distribution, version)
 	}
 
-	// Get the binary path
-	sdkPath, err := getSDKBinPath(installPath, sdkType)
+	// Load metadata for the installation
+	metadata, err := downloader.LoadMetadata(installPath)
 	if err != nil {
-		return fmt.Errorf("failed to find SDK binary path: %w", err)
+		logging.LogDebug("⚠️  Failed to load installation metadata: %v", err)
+		// Non-fatal, continue with default behavior
+	}
+
+	// Get the SDK home, single-file artifacts live directly in <install>/bin
+	sdkPath := installPath
+	if !sdkTypeConfig.IsBinary() {
+		sdkPath, err = downloader.HomeDir(installPath, metadata)
+		if err != nil {
+			return fmt.Errorf("failed to find SDK home: %w", err)
+		}
 	}
 
 	// Create the symbolic link
@@ -283,13 +271,6 @@ func handleUse(sdkType, distribution, version string) error {
 
 	logging.LogInfo("✅ Successfully set %s %s version %s as active", sdkType, distribution, version)
 
-	// Load metadata for the installation
-	metadata, err := downloader.LoadMetadata(installPath)
-	if err != nil {
-		logging.LogDebug("⚠️  Failed to load installation metadata: %v", err)
-		// Non-fatal, continue with default behavior
-	}
-
 	// If --set-env is specified, configure the environment variables
 	if setEnvVar {
 		if err := configureEnvironment(sdkType, sdkPath, metadata); err != nil {
@@ -311,12 +292,37 @@ func handleUse(sdkType, distribution, version string) error {
 			}
 			logging.LogInfo("")
 			logging.LogInfo("💡 Or use --set-env to set them automatically in your shell configuration")
+		} else if sdkTypeConfig.IsBinary() {
+			logging.LogInfo("ℹ️  To use this version, add it to your PATH:")
+			logging.LogInfo("   export PATH=%s/bin:$PATH", sdkPath)
+			logging.LogInfo("")
+			logging.LogInfo("💡 Or use --set-env to set it automatically in your shell configuration")
 		}
 	}
 
 	return nil
 }
 
+// resolveInstalled returns the newest installed version satisfying a version constraint
+func resolveInstalled(sdkType, distribution, requested string) (string, error) {
+	constraint, err := version.ParseConstraint(requested)
+	if err != nil {
+		return "", err
+	}
+
+	installed, err := installedVersions(cfg, sdkType, distribution)
+	if err != nil {
+		return "", err
+	}
+	basePath, err := GetInstallPath(cfg, sdkType, distribution, "")
+	if err != nil {
+		return "", err
+	}
+
+	// Variant builds (crac, musl...) are, as on install, only used when requested by name
+	return resolveConstraint(constraint, sdkType, distribution, downloader.PlainInstalls(basePath, installed), nil)
+}
+
 func configureEnvironment(sdkType, sdkPath string, metadata *downloader.SDKMetadata) error {
 	// Find the appropriate RC file
 	rcFile, err := findRcFile()
@@ -357,6 +363,9 @@ func configureEnvironment(sdkType, sdkPath string, metadata *downloader.SDKMetad
 			newConfig = fmt.Sprintf("\n# Added by Strigo - %s configuration\nexport %s=%s\nexport PATH=$%s/bin:$PATH\n",
 				strings.ToUpper(sdkType), envVar, sdkPath, envVar)
 		}
+	} else if metadata != nil && metadata.Artifact == config.ArtifactBinary {
+		newConfig = fmt.Sprintf("\n# Added by Strigo - %s configuration\nexport PATH=%s/bin:$PATH\n",
+			strings.ToUpper(sdkType), sdkPath)
 	}
 
 	// Remove the old configuration if it exists
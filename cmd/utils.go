@@ -6,7 +6,11 @@ import (
 	"os"
 	"path/filepath"
 	"strigo/config"
+	"strigo/downloader/progress"
 	"strigo/logging"
+	"strigo/repository"
+	"strigo/repository/version"
+	"strings"
 )
 
 // ListOutput structure for JSON output of list and available commands
@@ -44,6 +48,116 @@ func GetInstallPath(cfg *config.Config, sdkType, distribution, version string) (
 	), nil
 }
 
+// installedVersions returns the versions of a distribution present in the install tree
+func installedVersions(cfg *config.Config, sdkType, distribution string) ([]string, error) {
+	basePath, err := GetInstallPath(cfg, sdkType, distribution, "")
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(basePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read versions directory: %w", err)
+	}
+
+	var versions []string
+	for _, entry := range entries {
+		// Skip hidden entries such as staging directories of in-progress installs
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			versions = append(versions, entry.Name())
+		}
+	}
+	return versions, nil
+}
+
+// Platform selected with --os and --arch
+var (
+	targetOS   string
+	targetArch string
+)
+
+// targetPlatform returns the platform selected with --os and --arch, the host's for the parts
+// left unset. Vendor names such as "mac" or "x64" are accepted.
+func targetPlatform() repository.Platform {
+	platform := repository.HostPlatform()
+	if targetOS != "" {
+		platform.OS = repository.NormalizeOS(targetOS)
+	}
+	if targetArch != "" {
+		platform.Arch = repository.NormalizeArch(targetArch)
+	}
+	return platform
+}
+
+// lifecycle caches the lifecycle data loaded by getLifecycle
+var (
+	lifecycle       repository.Lifecycle
+	lifecycleLoaded bool
+)
+
+// getLifecycle returns the LTS and end-of-life data configured in [general], nil if none.
+// Lifecycle data is informational, so failing to load it is only logged.
+func getLifecycle() repository.Lifecycle {
+	if !lifecycleLoaded {
+		lifecycleLoaded = true
+		data, err := repository.LoadLifecycle(cfg)
+		if err != nil {
+			logging.LogDebug("⚠️  Lifecycle data unavailable: %v", err)
+		}
+		lifecycle = data
+	}
+	return lifecycle
+}
+
+// isLTSRelease reports whether a major version is a long-term support line, from the
+// lifecycle data if it knows the version, otherwise: Java 8, 11 and every fourth release
+// from 17, even-numbered Node.js releases
+func isLTSRelease(sdkType, distribution string, major int) bool {
+	if entry, ok := getLifecycle().Lookup(sdkType, distribution, major); ok {
+		return entry.LTS
+	}
+	switch sdkType {
+	case "jdk":
+		return major == 8 || major == 11 || (major >= 17 && (major-17)%4 == 0)
+	case "node":
+		return major > 0 && major%2 == 0
+	}
+	return false
+}
+
+// resolveConstraint returns the newest candidate satisfying a version constraint.
+// ltsVersions holds candidates flagged as LTS by the registry, in addition to the known
+// LTS lines of the distribution.
+func resolveConstraint(constraint version.Constraint, sdkType, distribution string, candidates []string, ltsVersions map[string]bool) (string, error) {
+	var accept func(version.Version) bool
+	if constraint.LTS {
+		accept = func(v version.Version) bool {
+			return ltsVersions[v.String()] || isLTSRelease(sdkType, distribution, v.Major())
+		}
+	}
+
+	resolved := constraint.Select(candidates, accept)
+	if resolved == "" {
+		if constraint.LTS {
+			return "", fmt.Errorf("no LTS release of %s %s found", sdkType, distribution)
+		}
+		return "", fmt.Errorf("no version matches constraint %s", constraint)
+	}
+	return resolved, nil
+}
+
+// newProgressReporter selects how download/extraction progress is shown:
+// NDJSON events with --json, a progress bar on interactive terminals, nothing otherwise
+func newProgressReporter() progress.Reporter {
+	if jsonOutput {
+		return progress.NewJSONReporter(os.Stdout)
+	}
+	if progress.IsTerminal(os.Stderr) {
+		return progress.NewBarReporter(os.Stderr)
+	}
+	return progress.NewNoopReporter()
+}
+
 // ExitWithError displays the error and exits with code 1
 func ExitWithError(err error) {
 	if jsonOutput {
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strigo/logging"
 	"strings"
+	"time"
 
 	"github.com/pelletier/go-toml"
 )
@@ -26,32 +27,154 @@ type GeneralConfig struct {
 	ShellConfigPath   string `toml:"shell_config_path"`
 	PatternsFile      string `toml:"patterns_file"` // Path to strigopatterns.toml (default: strigopatterns.toml)
 
+	// Optional LTS and end-of-life data, see docs/CONFIGURATION.md
+	LifecycleFile     string `toml:"lifecycle_file"`     // Path to a TOML or JSON lifecycle file
+	LifecycleURL      string `toml:"lifecycle_url"`      // URL the lifecycle data is fetched from, cached in cache_dir
+	LifecycleRegistry string `toml:"lifecycle_registry"` // Registry whose credentials are used for lifecycle_url
+	LifecycleTTL      string `toml:"lifecycle_ttl"`      // How long fetched lifecycle data is cached (default: "24h")
+
 	// Optional custom certificates with explicit aliases
 	CustomCertificates []CertificateEntry `toml:"custom_certificates"`
 	JDKCacertsOverride string             `toml:"jdk_cacerts_override"` // Optional CLI path override
 	JDKCacertsPassword string             `toml:"jdk_cacerts_password"` // Keystore password (default: "changeit")
 }
 
+// Artifact kinds of an SDK type
+const (
+	ArtifactArchive = "archive" // Archive extracted into the installation directory (default)
+	ArtifactBinary  = "binary"  // Single executable placed into <install>/bin/<name>
+)
+
 // SDKType represents a referenced SDK type configuration
 type SDKType struct {
 	Type       string `toml:"type"`
 	InstallDir string `toml:"install_dir"`
+	Artifact   string `toml:"artifact,omitempty"` // "archive" (default) or "binary"
+
+	StripComponents *StripComponents `toml:"strip_components,omitempty"` // Leading path components removed on extraction
+}
+
+// StripAuto strips the archive's top-level directory when all entries share a single one
+const StripAuto StripComponents = -1
+
+// StripComponents is a number of leading path components, or StripAuto ("auto" in TOML)
+type StripComponents int
+
+// UnmarshalTOML accepts a non-negative integer or "auto"
+func (s *StripComponents) UnmarshalTOML(value interface{}) error {
+	switch v := value.(type) {
+	case int64:
+		if v < 0 {
+			return fmt.Errorf("strip_components cannot be negative")
+		}
+		*s = StripComponents(v)
+	case string:
+		if v != "auto" {
+			return fmt.Errorf("invalid strip_components %q (expected a number or \"auto\")", v)
+		}
+		*s = StripAuto
+	default:
+		return fmt.Errorf("invalid strip_components %v (expected a number or \"auto\")", value)
+	}
+	return nil
+}
+
+// ResolveStripComponents returns the strip setting of a repository, falling back to its SDK type
+func ResolveStripComponents(sdkType SDKType, sdkRepo SDKRepository) StripComponents {
+	if sdkRepo.StripComponents != nil {
+		return *sdkRepo.StripComponents
+	}
+	if sdkType.StripComponents != nil {
+		return *sdkType.StripComponents
+	}
+	return 0
+}
+
+// IsBinary reports whether the SDK type is distributed as a single executable
+func (t SDKType) IsBinary() bool {
+	return t.Artifact == ArtifactBinary
 }
 
 // Registry represents a remote registry configuration
 type Registry struct {
 	Type     string `toml:"type"`
 	APIURL   string `toml:"api_url"`
-	Username string `toml:"username,omitempty"` // Optional: for authenticated registries
-	Password string `toml:"password,omitempty"` // Optional: for authenticated registries
+	Username string `toml:"username,omitempty"`  // Optional: for authenticated registries
+	Password string `toml:"password,omitempty"`  // Optional: for authenticated registries
+	Token    string `toml:"token,omitempty"`     // Optional: bearer token, takes precedence over username/password
+	APIKey   string `toml:"api_key,omitempty"`   // Optional: Artifactory API key (X-JFrog-Art-Api header)
+	MaxDepth *int   `toml:"max_depth,omitempty"` // Optional: directory levels crawled by http-index registries (default: 3)
+	Search   *bool  `toml:"search,omitempty"`    // Optional: query the Nexus search API instead of listing the repository
+
+	// Optional network tuning, durations use Go syntax ("10s", "2m")
+	ConnectTimeout        string `toml:"connect_timeout,omitempty"`         // TCP connection establishment
+	TLSHandshakeTimeout   string `toml:"tls_handshake_timeout,omitempty"`   // TLS handshake
+	ResponseHeaderTimeout string `toml:"response_header_timeout,omitempty"` // Wait for response headers after sending the request
+	IdleTimeout           string `toml:"idle_timeout,omitempty"`            // Maximum time without receiving any data
+	MaxRetries            *int   `toml:"max_retries,omitempty"`             // Retries on transient errors (0 disables)
+	RetryBackoff          string `toml:"retry_backoff,omitempty"`           // Initial backoff, doubled on each retry
+}
+
+// durationFields returns the registry duration settings keyed by their TOML name
+func (r Registry) durationFields() map[string]string {
+	return map[string]string{
+		"connect_timeout":         r.ConnectTimeout,
+		"tls_handshake_timeout":   r.TLSHandshakeTimeout,
+		"response_header_timeout": r.ResponseHeaderTimeout,
+		"idle_timeout":            r.IdleTimeout,
+		"retry_backoff":           r.RetryBackoff,
+	}
 }
 
 // SDKRepository represents a referenced SDK configuration
 type SDKRepository struct {
+	Name       string `toml:"-"` // Key of the repository in [sdk_repositories], set by LoadConfig
 	Type       string `toml:"type"`
-	Registry   string `toml:"registry"`
-	Repository string `toml:"repository"`
+	Registry   string   `toml:"registry"`
+	Registries []string `toml:"registries,omitempty"` // Fallback chain in priority order, instead of registry
+	Repository string   `toml:"repository"`
 	Path       string `toml:"path"`
+	BinaryName string `toml:"binary_name,omitempty"` // Executable name for binary artifacts (default: the distribution name)
+
+	// Maven coordinates, used by maven registries instead of Path
+	GroupID    string `toml:"group_id,omitempty"`
+	ArtifactID string `toml:"artifact_id,omitempty"`
+	Classifier string `toml:"classifier,omitempty"` // Optional, e.g. "bin" for apache-maven-3.9.9-bin.tar.gz
+	Extension  string `toml:"extension,omitempty"`  // Default: "tar.gz"
+
+	// Adoptium and Disco API filters
+	ImageType    string `toml:"image_type,omitempty"`   // jdk (default) or jre
+	ReleaseType  string `toml:"release_type,omitempty"` // ga (default) or ea
+	Distribution string `toml:"distribution,omitempty"` // Disco distribution (default: the repository name)
+
+	StripComponents *StripComponents `toml:"strip_components,omitempty"` // Overrides the SDK type setting
+}
+
+// RegistryNames returns the registries of the repository in priority order
+func (r SDKRepository) RegistryNames() []string {
+	if len(r.Registries) > 0 {
+		return r.Registries
+	}
+	return []string{r.Registry}
+}
+
+// NamedRegistry is a registry along with its name in the configuration
+type NamedRegistry struct {
+	Name string
+	Registry
+}
+
+// RegistriesFor returns the registries of a repository in priority order
+func (c *Config) RegistriesFor(repo SDKRepository) ([]NamedRegistry, error) {
+	var registries []NamedRegistry
+	for _, name := range repo.RegistryNames() {
+		registry, exists := c.Registries[name]
+		if !exists {
+			return nil, fmt.Errorf("registry %s not found", name)
+		}
+		registries = append(registries, NamedRegistry{Name: name, Registry: registry})
+	}
+	return registries, nil
 }
 
 // Config represents the main configuration structure
@@ -120,6 +243,12 @@ func LoadConfig(cliPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	// Repositories know their own name, used as a default by some registries
+	for name, repo := range cfg.SDKRepositories {
+		repo.Name = name
+		cfg.SDKRepositories[name] = repo
+	}
+
 	// Debug: Display decoded structure
 	logging.PreLog("DEBUG", "🔍 Decoded Config: %+v", cfg)
 
@@ -222,6 +351,67 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate lifecycle settings
+	if c.General.LifecycleTTL != "" {
+		if d, err := time.ParseDuration(c.General.LifecycleTTL); err != nil || d < 0 {
+			return fmt.Errorf("invalid lifecycle_ttl %q (expected a duration like \"24h\")", c.General.LifecycleTTL)
+		}
+	}
+	if c.General.LifecycleRegistry != "" {
+		if _, exists := c.Registries[c.General.LifecycleRegistry]; !exists {
+			return fmt.Errorf("lifecycle_registry %s not found", c.General.LifecycleRegistry)
+		}
+	}
+
+	// Validate SDK type artifact kinds
+	for name, sdkType := range c.SDKTypes {
+		switch sdkType.Artifact {
+		case "", ArtifactArchive, ArtifactBinary:
+		default:
+			return fmt.Errorf("sdk type %s: invalid artifact %q (expected %q or %q)", name, sdkType.Artifact, ArtifactArchive, ArtifactBinary)
+		}
+	}
+
+	// Validate registry network settings
+	for name, registry := range c.Registries {
+		for field, value := range registry.durationFields() {
+			if value == "" {
+				continue
+			}
+			if d, err := time.ParseDuration(value); err != nil || d < 0 {
+				return fmt.Errorf("registry %s: invalid %s %q (expected a duration like \"30s\")", name, field, value)
+			}
+		}
+		if registry.MaxRetries != nil && *registry.MaxRetries < 0 {
+			return fmt.Errorf("registry %s: max_retries cannot be negative", name)
+		}
+		if registry.MaxDepth != nil && *registry.MaxDepth < 0 {
+			return fmt.Errorf("registry %s: max_depth cannot be negative", name)
+		}
+	}
+
+	// Validate the repository settings required by their registry type
+	for name, repo := range c.SDKRepositories {
+		if repo.Registry != "" && len(repo.Registries) > 0 {
+			return fmt.Errorf("sdk repository %s: registry and registries cannot both be set", name)
+		}
+		for _, registryName := range repo.RegistryNames() {
+			registry, ok := c.Registries[registryName]
+			if !ok {
+				if len(repo.Registries) > 0 {
+					return fmt.Errorf("sdk repository %s: registry %s not found", name, registryName)
+				}
+				continue
+			}
+			switch registry.Type {
+			case "maven":
+				if repo.GroupID == "" || repo.ArtifactID == "" {
+					return fmt.Errorf("sdk repository %s: group_id and artifact_id are required for maven registries", name)
+				}
+			}
+		}
+	}
+
 	// Set default password if not provided
 	if c.General.JDKCacertsPassword == "" && len(c.General.CustomCertificates) > 0 {
 		c.General.JDKCacertsPassword = "changeit"
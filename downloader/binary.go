@@ -0,0 +1,50 @@
+package downloader
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strigo/logging"
+	"strings"
+)
+
+// BinaryDir is the directory of an installation holding single-file executables
+const BinaryDir = "bin"
+
+// InstallBinary copies a downloaded executable to <installPath>/bin/<name> with the executable bit set
+func InstallBinary(srcPath, installPath, name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsRune(name, os.PathSeparator) {
+		return fmt.Errorf("invalid binary name %q", name)
+	}
+
+	binDir := filepath.Join(installPath, BinaryDir)
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return fmt.Errorf("failed to create bin directory: %w", err)
+	}
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to open downloaded file: %w", err)
+	}
+	defer src.Close()
+
+	target := filepath.Join(binDir, name)
+	dst, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", target, err)
+	}
+	defer dst.Close()
+
+	written, err := io.Copy(dst, src)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	// Apply the mode explicitly, OpenFile is subject to the umask
+	if err := dst.Chmod(0755); err != nil {
+		return err
+	}
+
+	logging.LogDebug("📦 Installed binary %s (%d bytes)", target, written)
+	return dst.Close()
+}
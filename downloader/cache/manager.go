@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strigo/logging"
+	"time"
 )
 
 // Manager handles cache for downloaded files
@@ -35,6 +36,23 @@ func (m *Manager) CleanupCache(cachePath string, keepCache bool) error {
 	return nil
 }
 
+// QuarantineFile moves a file that failed verification out of the regular cache
+// into <cacheDir>/quarantine so it is never reused, and returns its new location
+func (m *Manager) QuarantineFile(filePath, cacheDir string) (string, error) {
+	quarantineDir := filepath.Join(cacheDir, "quarantine")
+	if err := os.MkdirAll(quarantineDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
+
+	target := filepath.Join(quarantineDir, fmt.Sprintf("%s.%s", filepath.Base(filePath), time.Now().Format("20060102_150405")))
+	if err := os.Rename(filePath, target); err != nil {
+		return "", fmt.Errorf("failed to quarantine file: %w", err)
+	}
+
+	logging.LogDebug("🚫 Quarantined %s to %s", filepath.Base(filePath), target)
+	return target, nil
+}
+
 func (m *Manager) cleanupCacheDirectory(cachePath string) error {
 	if err := os.RemoveAll(cachePath); err != nil {
 		return fmt.Errorf("failed to remove cache directory: %w", err)
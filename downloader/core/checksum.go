@@ -0,0 +1,51 @@
+package core
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// DefaultChecksumAlgorithm is used to fingerprint downloads when the registry
+// does not provide an expected digest
+const DefaultChecksumAlgorithm = "sha256"
+
+// NewHash returns a hash implementation for a checksum algorithm name
+// as reported by registries (sha512, sha256, sha1, md5)
+func NewHash(algorithm string) (hash.Hash, error) {
+	switch strings.ToLower(algorithm) {
+	case "sha512":
+		return sha512.New(), nil
+	case "sha256":
+		return sha256.New(), nil
+	case "sha1":
+		return sha1.New(), nil
+	case "md5":
+		return md5.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm: %s", algorithm)
+	}
+}
+
+// ChecksumMismatchError is returned when a downloaded file does not match its expected digest
+type ChecksumMismatchError struct {
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch: expected %s, got %s", e.Algorithm, e.Expected, e.Actual)
+}
+
+// VerifyChecksum compares an expected and an actual hex digest (case-insensitive)
+func VerifyChecksum(algorithm, expected, actual string) error {
+	if !strings.EqualFold(strings.TrimSpace(expected), actual) {
+		return &ChecksumMismatchError{Algorithm: algorithm, Expected: expected, Actual: actual}
+	}
+	return nil
+}
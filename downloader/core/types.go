@@ -1,8 +1,12 @@
 package core
 
+import "strigo/config"
+
 // DownloadOptions contains options for download and installation
 type DownloadOptions struct {
 	DownloadURL  string
+	FileName     string // Name of the file in the cache, defaults to the last segment of DownloadURL
+	Size         int64  // Size reported by the registry, 0 to ask the server
 	CacheDir     string
 	InstallPath  string
 	SDKType      string
@@ -11,4 +15,23 @@ type DownloadOptions struct {
 	KeepCache    bool
 	Username     string // HTTP Basic Auth username (optional)
 	Password     string // HTTP Basic Auth password (optional)
+
+	// Integrity verification
+	Checksum          string // Expected hex digest (optional)
+	ChecksumAlgorithm string // Algorithm of Checksum: sha512, sha256, sha1 or md5
+	SkipVerify        bool   // Skip checksum verification
+
+	// Leading path components removed from archive entries, or config.StripAuto
+	StripComponents config.StripComponents
+
+	// Single-file artifacts
+	Binary     bool   // Install the download as an executable instead of extracting it
+	BinaryName string // File name of the executable in <InstallPath>/bin
+}
+
+// DownloadResult describes a completed download and installation
+type DownloadResult struct {
+	Checksum          string // Hex digest of the downloaded archive
+	ChecksumAlgorithm string // Algorithm used to compute Checksum
+	Verified          bool   // Whether Checksum was verified against the registry
 }
@@ -0,0 +1,232 @@
+package downloader
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strigo/logging"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// extractRoot is the destination of an extraction. All entries written by the
+// Extractor, including link targets, must stay inside it.
+type extractRoot struct {
+	path string // destination as given by the caller
+	real string // destination with symlinks resolved, used for confinement checks
+}
+
+func newExtractRoot(destPath string) (*extractRoot, error) {
+	if err := os.MkdirAll(destPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create destination: %w", err)
+	}
+	real, err := filepath.EvalSymlinks(destPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve destination: %w", err)
+	}
+	return &extractRoot{path: filepath.Clean(destPath), real: real}, nil
+}
+
+// contains reports whether a path (with symlinks resolved) is inside the root
+func (r *extractRoot) contains(realPath string) bool {
+	return realPath == r.real || strings.HasPrefix(realPath, r.real+string(os.PathSeparator))
+}
+
+// join returns the destination of an archive entry, rejecting names escaping the root
+// (zip-slip / tar-slip). The returned path equals the root for "." or "./".
+func (r *extractRoot) join(name string) (string, error) {
+	target := filepath.Join(r.path, name)
+	if target != r.path && !strings.HasPrefix(target, r.path+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid archive path: %s", name)
+	}
+	return target, nil
+}
+
+// prepareParent creates the parent directory of target and checks that, once
+// symlinks already extracted are followed, it is still inside the root
+func (r *extractRoot) prepareParent(target string) (string, error) {
+	parent := filepath.Dir(target)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return "", err
+	}
+	realParent, err := filepath.EvalSymlinks(parent)
+	if err != nil {
+		return "", err
+	}
+	if !r.contains(realParent) {
+		return "", fmt.Errorf("path %s escapes destination through a symlink", target)
+	}
+	return realParent, nil
+}
+
+// removeExisting deletes a non-directory entry at target so it can be replaced
+func removeExisting(target string) error {
+	info, err := os.Lstat(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s already exists as a directory", target)
+	}
+	return os.Remove(target)
+}
+
+// sanitizeMode keeps permission bits only, dropping setuid, setgid and sticky bits
+func sanitizeMode(name string, mode os.FileMode) os.FileMode {
+	if mode&(os.ModeSetuid|os.ModeSetgid|os.ModeSticky) != 0 {
+		logging.LogDebug("🔒 Stripped special permission bits from %s", name)
+	}
+	return mode.Perm()
+}
+
+// writeDir creates a directory entry
+func (r *extractRoot) writeDir(target string, mode os.FileMode) error {
+	if _, err := r.prepareParent(target); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return err
+	}
+	// Keep directories traversable and writable by the owner so extraction can continue
+	return os.Chmod(target, mode|0700)
+}
+
+// writeFile creates a regular file entry from src
+func (r *extractRoot) writeFile(target string, src io.Reader, mode os.FileMode) (int64, error) {
+	if _, err := r.prepareParent(target); err != nil {
+		return 0, err
+	}
+	if err := removeExisting(target); err != nil {
+		return 0, err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|os.O_EXCL, mode)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	written, err := io.Copy(f, src)
+	if err != nil {
+		return written, err
+	}
+	// Apply the mode explicitly, OpenFile is subject to the umask
+	if err := f.Chmod(mode); err != nil {
+		return written, err
+	}
+	return written, f.Close()
+}
+
+// writeSymlink creates a symbolic link entry. The link target must resolve inside the root.
+func (r *extractRoot) writeSymlink(target, linkname string) error {
+	if filepath.IsAbs(linkname) {
+		return fmt.Errorf("symlink %s points to absolute path %s", target, linkname)
+	}
+
+	realParent, err := r.prepareParent(target)
+	if err != nil {
+		return err
+	}
+	resolved, err := resolveLink(realParent, linkname)
+	if err != nil || !r.contains(resolved) {
+		return fmt.Errorf("symlink %s points outside destination: %s", target, linkname)
+	}
+
+	if err := removeExisting(target); err != nil {
+		return err
+	}
+	return os.Symlink(linkname, target)
+}
+
+// resolveLink returns where the relative link target linkname of a symlink in dir leads, following
+// the symlinks already extracted on the way like the system will. A ".." after a component that
+// does not exist yet is rejected, since that component may be extracted as a symlink later.
+func resolveLink(dir, linkname string) (string, error) {
+	current := dir
+	parts := strings.Split(linkname, string(os.PathSeparator))
+	for i, part := range parts {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			current = filepath.Dir(current)
+			continue
+		}
+
+		next := filepath.Join(current, part)
+		if _, err := os.Lstat(next); os.IsNotExist(err) {
+			for _, rest := range parts[i+1:] {
+				if rest == ".." {
+					return "", fmt.Errorf("cannot resolve %s: %s does not exist", linkname, part)
+				}
+			}
+			return filepath.Join(append([]string{current}, parts[i:]...)...), nil
+		}
+		real, err := filepath.EvalSymlinks(next)
+		if err != nil {
+			return "", err
+		}
+		current = real
+	}
+	return current, nil
+}
+
+// writeHardlink creates a hard link entry. linkname is relative to the archive root.
+func (r *extractRoot) writeHardlink(target, linkname string) error {
+	source, err := r.join(linkname)
+	if err != nil {
+		return fmt.Errorf("hardlink %s: %w", target, err)
+	}
+
+	realSource, err := filepath.EvalSymlinks(source)
+	if err != nil {
+		return fmt.Errorf("hardlink %s: source %s not extracted: %w", target, linkname, err)
+	}
+	if !r.contains(realSource) {
+		return fmt.Errorf("hardlink %s points outside destination: %s", target, linkname)
+	}
+
+	if _, err := r.prepareParent(target); err != nil {
+		return err
+	}
+	if err := removeExisting(target); err != nil {
+		return err
+	}
+	return os.Link(realSource, target)
+}
+
+// restoreTimes sets the modification time of an extracted entry without following symlinks
+func restoreTimes(target string, modTime time.Time) error {
+	if modTime.IsZero() {
+		return nil
+	}
+	tv := unix.NsecToTimeval(modTime.UnixNano())
+	return unix.Lutimes(target, []unix.Timeval{tv, tv})
+}
+
+// deferredTimes collects directory mtimes, applied once extraction is complete
+// since writing entries into a directory updates its mtime
+type deferredTimes struct {
+	paths []string
+	times []time.Time
+}
+
+func (d *deferredTimes) add(path string, modTime time.Time) {
+	d.paths = append(d.paths, path)
+	d.times = append(d.times, modTime)
+}
+
+func (d *deferredTimes) apply() {
+	// Deepest directories first
+	for i := len(d.paths) - 1; i >= 0; i-- {
+		if err := restoreTimes(d.paths[i], d.times[i]); err != nil {
+			logging.LogDebug("⚠️  Failed to restore mtime of %s: %v", d.paths[i], err)
+		}
+	}
+}
@@ -2,23 +2,67 @@ package downloader
 
 import (
 	"archive/tar"
+	"compress/bzip2"
 	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
+	"strigo/config"
+	"strigo/downloader/progress"
 	"strigo/logging"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/ulikunitz/xz"
 )
 
 // Extractor handles archive extraction
-type Extractor struct{}
+type Extractor struct {
+	reporter        progress.Reporter
+	stripComponents config.StripComponents
+	ctx             context.Context
+}
 
 // NewExtractor creates a new Extractor instance
 func NewExtractor() *Extractor {
-	return &Extractor{}
+	return &Extractor{
+		reporter: progress.NewNoopReporter(),
+		ctx:      context.Background(),
+	}
+}
+
+// SetReporter sets the reporter receiving extraction progress
+func (e *Extractor) SetReporter(reporter progress.Reporter) {
+	e.reporter = reporter
+}
+
+// SetContext sets the context whose cancellation stops the extraction before the next entry
+func (e *Extractor) SetContext(ctx context.Context) {
+	e.ctx = ctx
+}
+
+// SetStripComponents sets the number of leading path components removed from
+// archive entries, or config.StripAuto to strip a single common top-level directory
+func (e *Extractor) SetStripComponents(strip config.StripComponents) {
+	e.stripComponents = strip
+}
+
+// openArchive opens an archive file and starts extraction progress reporting.
+// Progress is measured on the compressed bytes consumed from the file.
+func (e *Extractor) openArchive(archivePath string) (*os.File, io.Reader, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+
+	var size int64
+	if info, err := file.Stat(); err == nil {
+		size = info.Size()
+	}
+	e.reporter.Start(progress.PhaseExtract, size)
+
+	return file, progress.NewReader(file, e.reporter), nil
 }
 
 // Extract extracts an archive to a destination directory
@@ -27,54 +71,97 @@ func (e *Extractor) Extract(archivePath, destPath string) error {
 		return fmt.Errorf("destination path must be absolute")
 	}
 
-	logging.LogDebug(" Starting extraction of %s to %s", filepath.Base(archivePath), destPath)
+	format, err := DetectFormat(archivePath)
+	if err != nil {
+		return err
+	}
 
-	switch {
-	case strings.HasSuffix(archivePath, ".tar.gz"):
-		return e.extractTarGz(archivePath, destPath)
-	case strings.HasSuffix(archivePath, ".tar.xz"):
-		return e.extractTarXz(archivePath, destPath)
-	default:
+	logging.LogDebug(" Starting extraction of %s (%s) to %s", filepath.Base(archivePath), format, destPath)
+
+	if format == FormatZip {
+		return e.extractZip(archivePath, destPath)
+	}
+	if format == FormatUnknown {
 		return fmt.Errorf("unsupported archive format")
 	}
+	return e.extractCompressedTar(archivePath, destPath, format)
 }
 
-func (e *Extractor) extractTarGz(tarPath, destPath string) error {
-	logging.LogDebug(" Opening tar.gz archive: %s", filepath.Base(tarPath))
-	file, err := os.Open(tarPath)
-	if err != nil {
-		return fmt.Errorf("failed to open archive: %w", err)
+// extractCompressedTar decompresses a tar stream according to its detected format and extracts it
+func (e *Extractor) extractCompressedTar(tarPath, destPath string, format ArchiveFormat) error {
+	// With auto, the archive is extracted as is while watching its top level, and a single
+	// top-level directory is stripped afterwards, so the stream is only decompressed once
+	var scan *topLevelScan
+	strip := int(e.stripComponents)
+	if e.stripComponents == config.StripAuto {
+		scan = newTopLevelScan()
+		strip = 0
 	}
-	defer file.Close()
 
-	gzr, err := gzip.NewReader(file)
+	logging.LogDebug(" Opening %s archive: %s", format, filepath.Base(tarPath))
+	file, r, err := e.openArchive(tarPath)
 	if err != nil {
-		return fmt.Errorf("failed to create gzip reader: %w", err)
+		return err
 	}
-	defer gzr.Close()
-
-	return e.extractTar(tar.NewReader(gzr), destPath)
-}
+	defer file.Close()
 
-func (e *Extractor) extractTarXz(tarPath, destPath string) error {
-	logging.LogDebug(" Opening tar.xz archive: %s", filepath.Base(tarPath))
-	file, err := os.Open(tarPath)
+	stream, closeStream, err := newTarStream(r, format)
 	if err != nil {
-		return fmt.Errorf("failed to open archive: %w", err)
+		return err
 	}
-	defer file.Close()
+	defer closeStream()
 
-	xzr, err := xz.NewReader(file)
-	if err != nil {
-		return fmt.Errorf("failed to create xz reader: %w", err)
+	if err := e.extractTar(tar.NewReader(stream), destPath, strip, scan); err != nil {
+		return err
 	}
+	if scan != nil && scan.stripCount() == 1 {
+		return hoistTopLevel(destPath, scan.top, scan.symlinks)
+	}
+	return nil
+}
 
-	return e.extractTar(tar.NewReader(xzr), destPath)
+// newTarStream wraps r with the decompressor of format. The returned function releases it.
+func newTarStream(r io.Reader, format ArchiveFormat) (io.Reader, func(), error) {
+	switch format {
+	case FormatTar:
+		return r, func() {}, nil
+	case FormatTarGz:
+		gzr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		return gzr, func() { gzr.Close() }, nil
+	case FormatTarXz:
+		xzr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create xz reader: %w", err)
+		}
+		return xzr, func() {}, nil
+	case FormatTarBz2:
+		return bzip2.NewReader(r), func() {}, nil
+	case FormatTarZst:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		return zr, zr.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported archive format: %s", format)
+	}
 }
 
-func (e *Extractor) extractTar(tr *tar.Reader, destPath string) error {
+// extractTar extracts the entries of tr, reporting their names to scan when not nil
+func (e *Extractor) extractTar(tr *tar.Reader, destPath string, strip int, scan *topLevelScan) error {
 	var filesExtracted int
 	var totalSize int64
+	defer e.reporter.Finish()
+
+	root, err := newExtractRoot(destPath)
+	if err != nil {
+		return err
+	}
+	var dirTimes deferredTimes
+	defer dirTimes.apply()
 
 	logging.LogDebug(" Extracting files...")
 	for {
@@ -85,44 +172,66 @@ func (e *Extractor) extractTar(tr *tar.Reader, destPath string) error {
 		if err != nil {
 			return fmt.Errorf("failed to read tar header: %w", err)
 		}
+		if err := e.ctx.Err(); err != nil {
+			return err
+		}
+		// The global pax header written by git archive is metadata, not a top-level entry
+		if scan != nil && header.Typeflag != tar.TypeXGlobalHeader {
+			scan.add(header.Name, header.Typeflag == tar.TypeDir)
+		}
 
-		target := filepath.Join(destPath, header.Name)
-		if !strings.HasPrefix(target, filepath.Clean(destPath)+string(os.PathSeparator)) {
-			return fmt.Errorf("invalid tar path: %s", header.Name)
+		name, ok := stripEntryName(header.Name, strip)
+		if !ok {
+			continue
+		}
+		target, err := root.join(name)
+		if err != nil {
+			return err
+		}
+		if target == root.path {
+			// "./" entry describing the archive root itself
+			continue
 		}
 
 		switch header.Typeflag {
 		case tar.TypeDir:
-			if err := os.MkdirAll(target, 0755); err != nil {
+			if err := root.writeDir(target, sanitizeMode(header.Name, header.FileInfo().Mode())); err != nil {
 				return fmt.Errorf("failed to create directory: %w", err)
 			}
+			dirTimes.add(target, header.ModTime)
+			continue
 		case tar.TypeReg:
-			if err := e.extractFile(tr, target, header.Mode); err != nil {
+			written, err := root.writeFile(target, tr, sanitizeMode(header.Name, header.FileInfo().Mode()))
+			if err != nil {
 				return fmt.Errorf("failed to extract file: %w", err)
 			}
+			logging.LogDebug(" Extracted: %s (%d bytes)", filepath.Base(target), written)
 			filesExtracted++
 			totalSize += header.Size
+		case tar.TypeSymlink:
+			if err := root.writeSymlink(target, header.Linkname); err != nil {
+				return fmt.Errorf("failed to create symlink: %w", err)
+			}
+			if scan != nil {
+				scan.symlinks = append(scan.symlinks, target)
+			}
+		case tar.TypeLink:
+			linkname, ok := stripEntryName(header.Linkname, strip)
+			if !ok {
+				return fmt.Errorf("hardlink %s points to stripped entry %s", header.Name, header.Linkname)
+			}
+			if err := root.writeHardlink(target, linkname); err != nil {
+				return fmt.Errorf("failed to create hardlink: %w", err)
+			}
+		default:
+			logging.LogDebug("⚠️  Skipping unsupported tar entry %s (type %c)", header.Name, header.Typeflag)
+			continue
 		}
-	}
-	logging.LogDebug(" Extraction completed: %d files extracted, total size: %d bytes", filesExtracted, totalSize)
-	return nil
-}
-
-func (e *Extractor) extractFile(tr io.Reader, path string, mode int64) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return err
-	}
 
-	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, os.FileMode(mode))
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	written, err := io.Copy(f, tr)
-	if err != nil {
-		return err
+		if err := restoreTimes(target, header.ModTime); err != nil {
+			logging.LogDebug("⚠️  Failed to restore mtime of %s: %v", target, err)
+		}
 	}
-	logging.LogDebug(" Extracted: %s (%d bytes)", filepath.Base(path), written)
+	logging.LogDebug(" Extraction completed: %d files extracted, total size: %d bytes", filesExtracted, totalSize)
 	return nil
 }
@@ -0,0 +1,150 @@
+package downloader
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strigo/downloader/progress"
+	"strigo/logging"
+)
+
+// maxSymlinkTargetSize bounds the size of a zip symlink entry, whose content is the link target
+const maxSymlinkTargetSize = 4096
+
+func (e *Extractor) extractZip(zipPath, destPath string) error {
+	logging.LogDebug(" Opening zip archive: %s", filepath.Base(zipPath))
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return fmt.Errorf("failed to open zip archive: %w", err)
+	}
+	defer zr.Close()
+
+	strip, err := e.resolveStrip(func() (int, error) {
+		scan := newTopLevelScan()
+		for _, f := range zr.File {
+			scan.add(f.Name, f.Mode().IsDir())
+		}
+		return scan.stripCount(), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Zip entries are read through the central directory, so progress is reported per entry
+	var total int64
+	for _, f := range zr.File {
+		total += int64(f.CompressedSize64)
+	}
+	e.reporter.Start(progress.PhaseExtract, total)
+	defer e.reporter.Finish()
+
+	root, err := newExtractRoot(destPath)
+	if err != nil {
+		return err
+	}
+	var dirTimes deferredTimes
+	defer dirTimes.apply()
+
+	var filesExtracted int
+	var totalSize int64
+
+	logging.LogDebug(" Extracting files...")
+	for _, f := range zr.File {
+		if err := e.ctx.Err(); err != nil {
+			return err
+		}
+		name, ok := stripEntryName(f.Name, strip)
+		if !ok {
+			e.reporter.Add(int64(f.CompressedSize64))
+			continue
+		}
+		target, err := root.join(name)
+		if err != nil {
+			return err
+		}
+		if target == root.path {
+			continue
+		}
+
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			if err := root.writeDir(target, sanitizeMode(f.Name, mode)); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			dirTimes.add(target, f.Modified)
+			e.reporter.Add(int64(f.CompressedSize64))
+			continue
+		case mode&os.ModeSymlink != 0:
+			linkname, err := readZipSymlink(f)
+			if err != nil {
+				return err
+			}
+			if err := root.writeSymlink(target, linkname); err != nil {
+				return fmt.Errorf("failed to create symlink: %w", err)
+			}
+		case mode.IsRegular():
+			written, err := extractZipFile(root, f, target, zipFileMode(f))
+			if err != nil {
+				return fmt.Errorf("failed to extract file: %w", err)
+			}
+			logging.LogDebug(" Extracted: %s (%d bytes)", filepath.Base(target), written)
+			filesExtracted++
+			totalSize += written
+		default:
+			logging.LogDebug("⚠️  Skipping unsupported zip entry %s (mode %v)", f.Name, mode)
+			e.reporter.Add(int64(f.CompressedSize64))
+			continue
+		}
+
+		e.reporter.Add(int64(f.CompressedSize64))
+		if err := restoreTimes(target, f.Modified); err != nil {
+			logging.LogDebug("⚠️  Failed to restore mtime of %s: %v", target, err)
+		}
+	}
+	logging.LogDebug(" Extraction completed: %d files extracted, total size: %d bytes", filesExtracted, totalSize)
+	return nil
+}
+
+// zipFileMode returns the permissions of a regular zip entry. Archives created on
+// non-Unix systems carry no permission bits, default to 0644 for them.
+func zipFileMode(f *zip.File) os.FileMode {
+	if f.CreatorVersion>>8 != 3 { // 3 = Unix
+		return 0644
+	}
+	mode := sanitizeMode(f.Name, f.Mode())
+	if mode == 0 {
+		return 0644
+	}
+	return mode
+}
+
+func extractZipFile(root *extractRoot, f *zip.File, target string, mode os.FileMode) (int64, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	return root.writeFile(target, rc, mode)
+}
+
+// readZipSymlink returns the target of a symlink entry, stored as the entry content
+func readZipSymlink(f *zip.File) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to read symlink %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxSymlinkTargetSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read symlink %s: %w", f.Name, err)
+	}
+	if len(data) > maxSymlinkTargetSize {
+		return "", fmt.Errorf("symlink %s target is too long", f.Name)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,69 @@
+package downloader
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ArchiveFormat identifies the container and compression of a downloaded artifact
+type ArchiveFormat string
+
+const (
+	FormatUnknown ArchiveFormat = ""
+	FormatTar     ArchiveFormat = "tar"
+	FormatTarGz   ArchiveFormat = "tar.gz"
+	FormatTarXz   ArchiveFormat = "tar.xz"
+	FormatTarBz2  ArchiveFormat = "tar.bz2"
+	FormatTarZst  ArchiveFormat = "tar.zst"
+	FormatZip     ArchiveFormat = "zip"
+)
+
+var (
+	magicGzip  = []byte{0x1f, 0x8b}
+	magicXz    = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+	magicBzip2 = []byte("BZh")
+	magicZstd  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	magicZip   = []byte("PK\x03\x04")
+	// Empty zip archives only contain the end of central directory record
+	magicZipEmpty = []byte("PK\x05\x06")
+	// POSIX and GNU tar headers carry "ustar" at offset 257
+	magicTar       = []byte("ustar")
+	magicTarOffset = 257
+)
+
+// DetectFormat identifies an archive from its leading magic bytes, regardless of its file name
+func DetectFormat(archivePath string) (ArchiveFormat, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return FormatUnknown, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, magicTarOffset+len(magicTar))
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return FormatUnknown, fmt.Errorf("failed to read archive header: %w", err)
+	}
+	return detectFormat(header[:n]), nil
+}
+
+func detectFormat(header []byte) ArchiveFormat {
+	switch {
+	case bytes.HasPrefix(header, magicGzip):
+		return FormatTarGz
+	case bytes.HasPrefix(header, magicXz):
+		return FormatTarXz
+	case bytes.HasPrefix(header, magicBzip2):
+		return FormatTarBz2
+	case bytes.HasPrefix(header, magicZstd):
+		return FormatTarZst
+	case bytes.HasPrefix(header, magicZip), bytes.HasPrefix(header, magicZipEmpty):
+		return FormatZip
+	case len(header) >= magicTarOffset+len(magicTar) && bytes.Equal(header[magicTarOffset:], magicTar):
+		return FormatTar
+	default:
+		return FormatUnknown
+	}
+}
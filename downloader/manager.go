@@ -1,39 +1,42 @@
 package downloader
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strigo/downloader/cache"
 	"strigo/downloader/core"
 	"strigo/downloader/jdk"
 	"strigo/downloader/network"
+	"strigo/downloader/progress"
 	"strigo/logging"
 )
 
 // Manager orchestrates the download and installation process
 type Manager struct {
-	network     *network.Client
-	extractor   *Extractor
-	cache       *cache.Manager
-	validator   *core.Validator
+	network      *network.Client
+	extractor    *Extractor
+	cache        *cache.Manager
+	validator    *core.Validator
 	certificates *jdk.CertificateManager
 }
 
 // NewManager creates a new Manager instance
 func NewManager() *Manager {
-	return &Manager{
-		network:      network.NewClient(),
-		extractor:    NewExtractor(),
-		cache:        cache.NewManager(),
-		validator:    core.NewValidator(),
-		certificates: jdk.NewCertificateManager(),
-	}
+	return NewManagerWithSettings("", "", network.DefaultSettings())
 }
 
 // NewManagerWithAuth creates a new Manager instance with authentication
 func NewManagerWithAuth(username, password string) *Manager {
+	return NewManagerWithSettings(username, password, network.DefaultSettings())
+}
+
+// NewManagerWithSettings creates a new Manager instance with optional authentication
+// and custom network timeouts and retry policy
+func NewManagerWithSettings(username, password string, settings network.Settings) *Manager {
 	return &Manager{
-		network:      network.NewClientWithAuth(username, password),
+		network:      network.NewClientWithSettings(username, password, settings),
 		extractor:    NewExtractor(),
 		cache:        cache.NewManager(),
 		validator:    core.NewValidator(),
@@ -41,44 +44,104 @@ func NewManagerWithAuth(username, password string) *Manager {
 	}
 }
 
+// SetProgressReporter sets the reporter receiving download and extraction progress
+func (m *Manager) SetProgressReporter(reporter progress.Reporter) {
+	m.network.SetReporter(reporter)
+	m.extractor.SetReporter(reporter)
+}
+
+// SetContext sets the context whose cancellation (Ctrl-C) aborts the download and extraction
+func (m *Manager) SetContext(ctx context.Context) {
+	m.network.SetContext(ctx)
+	m.extractor.SetContext(ctx)
+}
+
+// IsMirrorFailure reports whether a DownloadAndExtract error is specific to the server the
+// file came from (unreachable, or a checksum mismatch), so another mirror may succeed
+func IsMirrorFailure(err error) bool {
+	var mismatch *core.ChecksumMismatchError
+	return errors.As(err, &mismatch) || network.IsUnreachable(err)
+}
+
 // DownloadAndExtract handles the complete download and installation process
-func (m *Manager) DownloadAndExtract(opts core.DownloadOptions) error {
+func (m *Manager) DownloadAndExtract(opts core.DownloadOptions) (*core.DownloadResult, error) {
 	logging.LogDebug("🔍 Starting installation process for %s %s %s", opts.SDKType, opts.Distribution, opts.Version)
 
-	// Check file size
-	fileSize, err := m.network.GetFileSize(opts.DownloadURL)
-	if err != nil {
-		return fmt.Errorf("failed to get file size: %w", err)
+	// Check file size, unless the registry already reported it
+	fileSize := opts.Size
+	if fileSize <= 0 {
+		var err error
+		fileSize, err = m.network.GetFileSize(opts.DownloadURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get file size: %w", err)
+		}
 	}
 
 	// Validate available space
 	if err := m.validator.ValidateSpace(fileSize, opts.CacheDir); err != nil {
-		return fmt.Errorf("cache directory space check failed: %w", err)
+		return nil, fmt.Errorf("cache directory space check failed: %w", err)
 	}
 	if err := m.validator.ValidateSpace(fileSize, filepath.Dir(opts.InstallPath)); err != nil {
-		return fmt.Errorf("install directory space check failed: %w", err)
+		return nil, fmt.Errorf("install directory space check failed: %w", err)
 	}
 
 	// Prepare cache
 	cachePath, err := m.cache.PrepareCacheDirectory(opts.SDKType, opts.Distribution, opts.Version, opts.CacheDir)
 	if err != nil {
-		return fmt.Errorf("failed to prepare cache: %w", err)
+		return nil, fmt.Errorf("failed to prepare cache: %w", err)
+	}
+
+	// Hash with the registry-provided algorithm, or fingerprint with the default one
+	algorithm := opts.ChecksumAlgorithm
+	if opts.Checksum == "" || algorithm == "" {
+		algorithm = core.DefaultChecksumAlgorithm
 	}
 
 	// Download file
-	cacheFile := filepath.Join(cachePath, filepath.Base(opts.DownloadURL))
-	if err := m.network.DownloadFile(opts.DownloadURL, cacheFile); err != nil {
-		return fmt.Errorf("download failed: %w", err)
+	cacheFile := filepath.Join(cachePath, cacheFileName(opts))
+	digest, err := m.network.DownloadFile(opts.DownloadURL, cacheFile, algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+
+	result := &core.DownloadResult{
+		Checksum:          digest,
+		ChecksumAlgorithm: algorithm,
+	}
+
+	// Verify integrity against the registry checksum
+	switch {
+	case opts.SkipVerify:
+		logging.LogInfo("⚠️  Checksum verification skipped (--skip-verify)")
+	case opts.Checksum == "":
+		logging.LogDebug("⚠️  No checksum provided by registry, skipping verification")
+	default:
+		if err := core.VerifyChecksum(algorithm, opts.Checksum, digest); err != nil {
+			if quarantined, qErr := m.cache.QuarantineFile(cacheFile, opts.CacheDir); qErr != nil {
+				logging.LogDebug("⚠️ Failed to quarantine %s: %v", cacheFile, qErr)
+			} else {
+				logging.LogInfo("🚫 Corrupted download moved to %s", quarantined)
+			}
+			return nil, fmt.Errorf("integrity check failed: %w", err)
+		}
+		result.Verified = true
+		logging.LogInfo("🔒 Verified %s checksum", algorithm)
 	}
 
 	// Validate and create installation directory
 	if err := m.validator.ValidateDirectories(opts.InstallPath); err != nil {
-		return fmt.Errorf("failed to prepare installation directory: %w", err)
+		return nil, fmt.Errorf("failed to prepare installation directory: %w", err)
 	}
 
-	// Extract archive
-	if err := m.extractor.Extract(cacheFile, opts.InstallPath); err != nil {
-		return fmt.Errorf("extraction failed: %w", err)
+	if opts.Binary {
+		if err := InstallBinary(cacheFile, opts.InstallPath, opts.BinaryName); err != nil {
+			return nil, fmt.Errorf("binary installation failed: %w", err)
+		}
+	} else {
+		m.extractor.SetStripComponents(opts.StripComponents)
+		if err := m.extractor.Extract(cacheFile, opts.InstallPath); err != nil {
+			return nil, fmt.Errorf("extraction failed: %w", err)
+		}
 	}
 
 	// Clean cache if needed
@@ -90,5 +153,14 @@ func (m *Manager) DownloadAndExtract(opts core.DownloadOptions) error {
 	// This allows for proper path detection and optional certificate management
 
 	logging.LogInfo("✅ Successfully extracted %s %s version %s", opts.SDKType, opts.Distribution, opts.Version)
-	return nil
+	return result, nil
+}
+
+// cacheFileName returns the name the download is cached under: opts.FileName when it is a
+// plain file name, otherwise the last segment of the download URL
+func cacheFileName(opts core.DownloadOptions) string {
+	if name := filepath.Base(opts.FileName); opts.FileName != "" && name == opts.FileName && name != "." && name != ".." {
+		return name
+	}
+	return filepath.Base(opts.DownloadURL)
 }
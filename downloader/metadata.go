@@ -2,8 +2,10 @@ package downloader
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // SDKMetadata contains metadata about an installed SDK
@@ -11,6 +13,16 @@ type SDKMetadata struct {
 	SDKType      string `json:"sdk_type"`
 	Distribution string `json:"distribution"`
 	Version      string `json:"version"`
+	Requested    string `json:"requested,omitempty"` // Constraint or alias Version was resolved from ("17", "lts")
+	Artifact     string `json:"artifact,omitempty"`  // "binary" for single-file installs
+	HomePath     string `json:"home_path,omitempty"` // SDK home relative to the installation directory ("." for the directory itself)
+	Platform     string `json:"platform,omitempty"`  // "os/arch" the SDK was built for, when not the host's
+	Variant      string `json:"variant,omitempty"`   // Flavour of the build (crac, musl...), empty for plain builds
+
+	// Archive integrity
+	Checksum          string `json:"checksum,omitempty"`           // Hex digest of the downloaded archive
+	ChecksumAlgorithm string `json:"checksum_algorithm,omitempty"` // Algorithm used for Checksum
+	ChecksumVerified  bool   `json:"checksum_verified"`            // Digest matched the registry checksum
 
 	// Node.js specific
 	NodeExtraCaCerts string `json:"node_extra_ca_certs,omitempty"` // Path to PEM bundle
@@ -47,3 +59,60 @@ func LoadMetadata(installPath string) (*SDKMetadata, error) {
 
 	return &metadata, nil
 }
+
+// PlainInstalls returns the installations among names in basePath that are not variant builds
+// (crac, musl...), according to their metadata. Installations without metadata are plain builds.
+func PlainInstalls(basePath string, names []string) []string {
+	var plain []string
+	for _, name := range names {
+		metadata, err := LoadMetadata(filepath.Join(basePath, name))
+		if err == nil && metadata != nil && metadata.Variant != "" {
+			continue
+		}
+		plain = append(plain, name)
+	}
+	return plain
+}
+
+// FindHome returns the SDK home of an extracted installation, relative to installPath.
+// An installation holding a single top-level directory (as most archives without
+// strip_components do) has its home in that directory, otherwise in installPath itself.
+func FindHome(installPath string) (string, error) {
+	entries, err := os.ReadDir(installPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read installation directory: %w", err)
+	}
+
+	var dirs, others int
+	var home string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			// Strigo bookkeeping files (metadata, staging marker)
+			continue
+		}
+		if entry.IsDir() {
+			dirs++
+			home = entry.Name()
+		} else {
+			others++
+		}
+	}
+
+	if dirs == 1 && others == 0 {
+		return home, nil
+	}
+	return ".", nil
+}
+
+// HomeDir returns the absolute SDK home of an installation, using the recorded
+// home path when available and detecting it for older installations
+func HomeDir(installPath string, metadata *SDKMetadata) (string, error) {
+	if metadata != nil && metadata.HomePath != "" {
+		return filepath.Join(installPath, metadata.HomePath), nil
+	}
+	home, err := FindHome(installPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(installPath, home), nil
+}
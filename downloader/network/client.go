@@ -1,11 +1,17 @@
 package network
 
 import (
+	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"net/http"
 	"os"
 	"strconv"
+	"strigo/downloader/core"
+	"strigo/downloader/progress"
 	"strigo/logging"
 	"time"
 )
@@ -15,48 +21,130 @@ type Client struct {
 	httpClient *http.Client
 	username   string
 	password   string
+	settings   Settings
+	reporter   progress.Reporter
+	ctx        context.Context
 }
 
 // NewClient creates a new Client instance without authentication
 func NewClient() *Client {
-	return &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-	}
+	return NewClientWithSettings("", "", DefaultSettings())
 }
 
 // NewClientWithAuth creates a new Client instance with HTTP Basic Authentication
 func NewClientWithAuth(username, password string) *Client {
+	return NewClientWithSettings(username, password, DefaultSettings())
+}
+
+// NewClientWithSettings creates a new Client instance with custom timeouts and retry policy.
+// username and password can be empty for anonymous access.
+func NewClientWithSettings(username, password string, settings Settings) *Client {
 	return &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		username: username,
-		password: password,
+		httpClient: newHTTPClient(settings),
+		username:   username,
+		password:   password,
+		settings:   settings,
+		reporter:   progress.NewNoopReporter(),
+		ctx:        context.Background(),
 	}
 }
 
-// GetFileSize retrieves the size of a remote file
-func (c *Client) GetFileSize(url string) (int64, error) {
-	req, err := http.NewRequest("HEAD", url, nil)
+// SetReporter sets the reporter receiving download progress
+func (c *Client) SetReporter(reporter progress.Reporter) {
+	c.reporter = reporter
+}
+
+// SetContext sets the context whose cancellation aborts requests, transfers and retry waits
+func (c *Client) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// send performs a single request, adding the configured headers and Basic Auth
+// if credentials are provided and no token is configured
+func (c *Client) send(method, url string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(c.ctx, method, url, nil)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Add Basic Auth if credentials are provided
-	if c.username != "" && c.password != "" {
+	for _, h := range []http.Header{c.settings.Headers, header} {
+		for key, values := range h {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+	}
+
+	if c.username != "" && c.password != "" && req.Header.Get("Authorization") == "" {
 		req.SetBasicAuth(c.username, c.password)
-		logging.LogDebug("🔐 Using Basic Auth for file size check")
+		logging.LogDebug("🔐 Using Basic Auth for %s %s", method, url)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// Do performs a request, retrying with exponential backoff on transient errors
+// (connection resets, timeouts, 5xx, 429 with Retry-After).
+// When retries are exhausted on a transient status, the last response is returned.
+func (c *Client) Do(method, url string, header http.Header) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(method, url, header)
+		transient := classify(resp, err)
+		if transient == nil || attempt > c.settings.Retry.MaxRetries {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		c.wait(attempt, transient, method+" "+url)
+	}
+}
+
+// classify returns a transientError if the outcome of a request can be retried
+func classify(resp *http.Response, err error) *transientError {
+	if err != nil {
+		if IsTransientError(err) {
+			return &transientError{err: err}
+		}
+		return nil
+	}
+	if IsTransientStatus(resp.StatusCode) {
+		return &transientError{
+			err:        fmt.Errorf("server returned %s", resp.Status),
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	return nil
+}
+
+// wait sleeps before retry number attempt
+func (c *Client) wait(attempt int, cause *transientError, what string) {
+	delay := c.settings.Retry.Backoff(attempt, cause.retryAfter)
+	logging.LogInfo("🔁 %s failed (%v), retrying in %s (%d/%d)", what, cause.err, delay.Round(time.Millisecond), attempt, c.settings.Retry.MaxRetries)
+	select {
+	case <-time.After(delay):
+	case <-c.ctx.Done():
+	}
+}
+
+// GetFileSize retrieves the size of a remote file
+func (c *Client) GetFileSize(url string) (int64, error) {
+	if path, ok := LocalPath(url); ok {
+		return localFileSize(path)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.Do("HEAD", url, nil)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get file size: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		// A server still failing after the retries is unreachable, like for the download itself
+		if transient := classify(resp, nil); transient != nil {
+			return 0, transient
+		}
 		return 0, fmt.Errorf("server returned non-OK status: %s", resp.Status)
 	}
 
@@ -68,42 +156,164 @@ func (c *Client) GetFileSize(url string) (int64, error) {
 	return size, nil
 }
 
-// DownloadFile downloads a file from a URL
-func (c *Client) DownloadFile(url, filepath string) error {
+// DownloadFile downloads a file from a URL.
+// If algorithm is not empty, the file is hashed while streaming and the hex digest is returned.
+//
+// Data is written to <filepath>.part and renamed into place once complete. If a partial
+// file from an interrupted download exists and the server supports byte ranges, the
+// download resumes with a Range request validated by ETag/Last-Modified (If-Range).
+//
+// Transient failures, including a connection dropped mid-transfer, are retried
+// according to the client's retry policy; retries resume from the partial file.
+// A partial file the server refuses to resume is discarded and downloaded again at once.
+//
+// file:// URLs are hardlinked or copied instead.
+func (c *Client) DownloadFile(url, filepath, algorithm string) (string, error) {
+	if path, ok := LocalPath(url); ok {
+		return c.fetchLocalFile(path, filepath, algorithm)
+	}
+
+	for attempt := 1; ; attempt++ {
+		digest, err := c.downloadOnce(url, filepath, algorithm)
+		if errors.Is(err, errResumeRejected) {
+			// Start over from zero right away, this is not a failure of the server
+			logging.LogDebug("🔁 %v, restarting download from zero", err)
+			digest, err = c.downloadOnce(url, filepath, algorithm)
+		}
+		var transient *transientError
+		if !errors.As(err, &transient) || attempt > c.settings.Retry.MaxRetries {
+			return digest, err
+		}
+		c.wait(attempt, transient, "download of "+url)
+	}
+}
+
+// downloadOnce performs a single download attempt
+func (c *Client) downloadOnce(url, filepath, algorithm string) (string, error) {
 	logging.LogDebug("📡 Initiating network request to %s", url)
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	var hasher hash.Hash
+	if algorithm != "" {
+		h, err := core.NewHash(algorithm)
+		if err != nil {
+			return "", err
+		}
+		hasher = h
 	}
 
-	// Add Basic Auth if credentials are provided
-	if c.username != "" && c.password != "" {
-		req.SetBasicAuth(c.username, c.password)
-		logging.LogDebug("🔐 Using Basic Auth for download")
+	partPath := filepath + PartSuffix
+	state, offset := loadPartState(partPath, url)
+
+	header := http.Header{}
+	if state != nil {
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		header.Set("If-Range", state.ifRangeValidator())
+		logging.LogDebug("⏯️  Resuming download from byte %d", offset)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send("GET", url, header)
+	if transient := classify(resp, err); transient != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return "", transient
+	}
 	if err != nil {
-		return fmt.Errorf("network request failed: %w", err)
+		return "", fmt.Errorf("network request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("server returned non-OK status: %s", resp.Status)
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && state != nil:
+		start, err := parseContentRangeStart(resp.Header.Get("Content-Range"))
+		if err != nil || start != offset || !sameValidators(state, resp.Header) {
+			removePartFiles(partPath)
+			return "", fmt.Errorf("server returned an unexpected partial response: %w", errResumeRejected)
+		}
+		flags = os.O_WRONLY | os.O_APPEND
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && state != nil:
+		// The partial file does not match the remote file anymore
+		removePartFiles(partPath)
+		return "", fmt.Errorf("server rejected resume request (%s): %w", resp.Status, errResumeRejected)
+	case resp.StatusCode == http.StatusOK:
+		if state != nil {
+			logging.LogDebug("🔁 Server sent the full file, restarting download from zero")
+		}
+		offset = 0
+	default:
+		return "", fmt.Errorf("server returned non-OK status: %s", resp.Status)
+	}
+
+	if offset == 0 {
+		// Record validators so an interrupted download can be resumed later
+		if err := savePartState(partPath, partState{
+			URL:          url,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			AcceptRanges: resp.Header.Get("Accept-Ranges") == "bytes",
+		}); err != nil {
+			logging.LogDebug("⚠️  Failed to save resume state: %v", err)
+		}
+	} else if hasher != nil {
+		if err := hashExisting(partPath, hasher); err != nil {
+			return "", fmt.Errorf("failed to read partial download: %w", err)
+		}
 	}
 
-	out, err := os.Create(filepath)
+	out, err := os.OpenFile(partPath, flags, 0644)
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return "", fmt.Errorf("failed to create output file: %w", err)
 	}
 	defer out.Close()
 
-	written, err := io.Copy(out, resp.Body)
+	var dst io.Writer = out
+	if hasher != nil {
+		dst = io.MultiWriter(out, hasher)
+	}
+
+	total := resp.ContentLength
+	if total > 0 {
+		total += offset
+	}
+	c.reporter.Start(progress.PhaseDownload, total)
+	c.reporter.Add(offset)
+
+	written, err := io.Copy(dst, progress.NewReader(resp.Body, c.reporter))
+	c.reporter.Finish()
 	if err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+		err = fmt.Errorf("failed to write file (%d bytes kept for resume): %w", offset+written, err)
+		if IsTransientError(err) {
+			return "", &transientError{err: err}
+		}
+		return "", err
 	}
 
-	logging.LogDebug("✅ Download completed. Wrote %d bytes", written)
-	return nil
+	if err := out.Close(); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(partPath, filepath); err != nil {
+		return "", fmt.Errorf("failed to finalize download: %w", err)
+	}
+	os.Remove(statePath(partPath))
+
+	logging.LogDebug("✅ Download completed. Wrote %d bytes (%d resumed)", offset+written, offset)
+
+	if hasher == nil {
+		return "", nil
+	}
+	digest := hex.EncodeToString(hasher.Sum(nil))
+	logging.LogDebug("🔑 %s: %s", algorithm, digest)
+	return digest, nil
+}
+
+// sameValidators checks that a partial response belongs to the same remote file
+func sameValidators(state *partState, header http.Header) bool {
+	if etag := header.Get("ETag"); etag != "" && state.ETag != "" {
+		return etag == state.ETag
+	}
+	if lastModified := header.Get("Last-Modified"); lastModified != "" && state.LastModified != "" {
+		return lastModified == state.LastModified
+	}
+	return true
 }
@@ -0,0 +1,120 @@
+package network
+
+import (
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"os"
+	"strigo/downloader/core"
+	"strigo/downloader/progress"
+	"strigo/logging"
+)
+
+// LocalPath returns the filesystem path of a file:// URL
+func LocalPath(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return "", false
+	}
+	return u.Path, true
+}
+
+// FileURL returns the file:// URL of an absolute path
+func FileURL(path string) string {
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// localFileSize returns the size of a file referenced by a file:// URL
+func localFileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get file size: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return 0, fmt.Errorf("%s is not a regular file", path)
+	}
+	return info.Size(), nil
+}
+
+// fetchLocalFile places a local file at dest, hardlinking it when source and
+// destination share a filesystem and copying it otherwise. The file is hashed
+// with algorithm, if not empty.
+func (c *Client) fetchLocalFile(source, dest, algorithm string) (string, error) {
+	var hasher hash.Hash
+	if algorithm != "" {
+		h, err := core.NewHash(algorithm)
+		if err != nil {
+			return "", err
+		}
+		hasher = h
+	}
+
+	size, err := localFileSize(source)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.Remove(dest); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to replace %s: %w", dest, err)
+	}
+
+	src, err := os.Open(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", source, err)
+	}
+	defer src.Close()
+
+	c.reporter.Start(progress.PhaseDownload, size)
+	defer c.reporter.Finish()
+	reader := progress.NewReader(src, c.reporter)
+
+	if err := os.Link(source, dest); err == nil {
+		logging.LogDebug("🔗 Hardlinked %s into cache", source)
+		if hasher != nil {
+			if _, err := io.Copy(hasher, reader); err != nil {
+				return "", fmt.Errorf("failed to read %s: %w", source, err)
+			}
+		}
+	} else {
+		logging.LogDebug("📋 Copying %s into cache (hardlink not possible: %v)", source, err)
+		if err := copyToFile(reader, dest, hasher); err != nil {
+			return "", err
+		}
+	}
+
+	if hasher == nil {
+		return "", nil
+	}
+	digest := hex.EncodeToString(hasher.Sum(nil))
+	logging.LogDebug("🔑 %s: %s", algorithm, digest)
+	return digest, nil
+}
+
+// copyToFile writes r to dest through a .part file renamed into place once complete
+func copyToFile(r io.Reader, dest string, hasher hash.Hash) error {
+	partPath := dest + PartSuffix
+	out, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer out.Close()
+
+	var dst io.Writer = out
+	if hasher != nil {
+		dst = io.MultiWriter(out, hasher)
+	}
+	if _, err := io.Copy(dst, r); err != nil {
+		os.Remove(partPath)
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(partPath)
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return os.Rename(partPath, dest)
+}
@@ -0,0 +1,109 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strigo/logging"
+)
+
+// PartSuffix is appended to the destination path while a download is in progress
+const PartSuffix = ".part"
+
+// partState holds the validators needed to safely resume a partial download.
+// It is stored next to the .part file as <file>.part.json
+type partState struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	AcceptRanges bool   `json:"accept_ranges"`
+}
+
+func statePath(partPath string) string {
+	return partPath + ".json"
+}
+
+// loadPartState returns the saved state of a partial download and the number of bytes
+// already on disk. It returns nil if the download cannot be resumed.
+func loadPartState(partPath, url string) (*partState, int64) {
+	info, err := os.Stat(partPath)
+	if err != nil || info.Size() == 0 {
+		return nil, 0
+	}
+
+	data, err := os.ReadFile(statePath(partPath))
+	if err != nil {
+		logging.LogDebug("⚠️  No resume state for %s, restarting download", partPath)
+		return nil, 0
+	}
+
+	var state partState
+	if err := json.Unmarshal(data, &state); err != nil {
+		logging.LogDebug("⚠️  Invalid resume state for %s: %v", partPath, err)
+		return nil, 0
+	}
+
+	// Without a strong ETag or Last-Modified, If-Range cannot tell whether the file changed
+	if state.URL != url || !state.AcceptRanges || state.ifRangeValidator() == "" {
+		logging.LogDebug("⚠️  Partial download of %s is not resumable, restarting download", partPath)
+		return nil, 0
+	}
+
+	return &state, info.Size()
+}
+
+// savePartState persists resume validators for a partial download
+func savePartState(partPath string, state partState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(statePath(partPath), data, 0644)
+}
+
+// errResumeRejected reports that the server could not resume a partial download, which was discarded
+var errResumeRejected = errors.New("partial download discarded")
+
+// removePartFiles deletes a partial download and its resume state
+func removePartFiles(partPath string) {
+	os.Remove(partPath)
+	os.Remove(statePath(partPath))
+}
+
+// ifRangeValidator returns the value for the If-Range header.
+// Strong ETags are preferred; weak ETags are not allowed in If-Range.
+func (s *partState) ifRangeValidator() string {
+	if s.ETag != "" && !isWeakETag(s.ETag) {
+		return s.ETag
+	}
+	return s.LastModified
+}
+
+func isWeakETag(etag string) bool {
+	return len(etag) > 2 && etag[:2] == "W/"
+}
+
+// parseContentRangeStart extracts the first byte position from a Content-Range header
+// (e.g. "bytes 100-199/200" → 100)
+func parseContentRangeStart(header string) (int64, error) {
+	var start, end int64
+	var total string
+	if _, err := fmt.Sscanf(header, "bytes %d-%d/%s", &start, &end, &total); err != nil {
+		return 0, fmt.Errorf("invalid Content-Range %q: %w", header, err)
+	}
+	return start, nil
+}
+
+// hashExisting feeds the bytes already downloaded into w (typically a hash)
+func hashExisting(partPath string, w io.Writer) error {
+	f, err := os.Open(partPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
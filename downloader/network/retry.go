@@ -0,0 +1,109 @@
+package network
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures retries with exponential backoff and jitter
+type RetryPolicy struct {
+	MaxRetries     int           // Number of retries after the first attempt (0 disables retries)
+	InitialBackoff time.Duration // Delay before the first retry, doubled for each subsequent retry
+	MaxBackoff     time.Duration // Upper bound for a single delay (including Retry-After)
+}
+
+// DefaultRetryPolicy returns the retry policy used when a registry does not override it
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// Backoff returns the delay before retry number attempt (starting at 1).
+// A positive retryAfter (from a Retry-After header) takes precedence over the computed delay.
+func (p RetryPolicy) Backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		if p.MaxBackoff > 0 && retryAfter > p.MaxBackoff {
+			return p.MaxBackoff
+		}
+		return retryAfter
+	}
+
+	delay := p.InitialBackoff << uint(attempt-1)
+	if delay <= 0 || (p.MaxBackoff > 0 && delay > p.MaxBackoff) {
+		delay = p.MaxBackoff
+	}
+
+	// Jitter: wait between 50% and 100% of the computed delay
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// IsTransientStatus reports whether an HTTP status is worth retrying
+func IsTransientStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusRequestTimeout || code >= 500
+}
+
+// IsTransientError reports whether a network error is worth retrying
+// (timeouts, connection resets, truncated responses)
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// IsUnreachable reports whether an error means the server could not deliver the file:
+// a transient failure that outlasted the retries, or a connection error
+func IsUnreachable(err error) bool {
+	var transient *transientError
+	return errors.As(err, &transient) || IsTransientError(err)
+}
+
+// parseRetryAfter decodes a Retry-After header (delay in seconds or HTTP date)
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// transientError marks a failure that can be retried, optionally after
+// the delay requested by the server
+type transientError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
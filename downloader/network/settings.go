@@ -0,0 +1,117 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strigo/config"
+	"time"
+)
+
+// Settings holds per-phase timeouts and the retry policy of a Client.
+//
+// There is deliberately no overall request timeout: a large SDK download on a slow
+// link may take minutes, which is fine as long as data keeps flowing.
+type Settings struct {
+	ConnectTimeout        time.Duration // TCP connection establishment
+	TLSHandshakeTimeout   time.Duration // TLS handshake
+	ResponseHeaderTimeout time.Duration // Wait for response headers once the request is sent
+	IdleTimeout           time.Duration // Maximum time between two successful reads
+	Retry                 RetryPolicy
+	Headers               http.Header // Sent with every request (token authentication)
+}
+
+// DefaultSettings returns the settings used when a registry does not override them
+func DefaultSettings() Settings {
+	return Settings{
+		ConnectTimeout:        10 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		IdleTimeout:           60 * time.Second,
+		Retry:                 DefaultRetryPolicy(),
+	}
+}
+
+// SettingsFromRegistry applies the network overrides of a registry on top of the defaults
+func SettingsFromRegistry(registry config.Registry) (Settings, error) {
+	settings := DefaultSettings()
+
+	overrides := []struct {
+		name   string
+		value  string
+		target *time.Duration
+	}{
+		{"connect_timeout", registry.ConnectTimeout, &settings.ConnectTimeout},
+		{"tls_handshake_timeout", registry.TLSHandshakeTimeout, &settings.TLSHandshakeTimeout},
+		{"response_header_timeout", registry.ResponseHeaderTimeout, &settings.ResponseHeaderTimeout},
+		{"idle_timeout", registry.IdleTimeout, &settings.IdleTimeout},
+		{"retry_backoff", registry.RetryBackoff, &settings.Retry.InitialBackoff},
+	}
+	for _, o := range overrides {
+		if o.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(o.value)
+		if err != nil {
+			return settings, fmt.Errorf("invalid %s %q: %w", o.name, o.value, err)
+		}
+		*o.target = d
+	}
+
+	if registry.MaxRetries != nil {
+		settings.Retry.MaxRetries = *registry.MaxRetries
+	}
+
+	if registry.Token != "" || registry.APIKey != "" {
+		settings.Headers = http.Header{}
+		if registry.Token != "" {
+			settings.Headers.Set("Authorization", "Bearer "+registry.Token)
+		}
+		if registry.APIKey != "" {
+			settings.Headers.Set("X-JFrog-Art-Api", registry.APIKey)
+		}
+	}
+
+	return settings, nil
+}
+
+// newHTTPClient builds an http.Client enforcing the phase timeouts of settings
+func newHTTPClient(settings Settings) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   settings.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			if err != nil || settings.IdleTimeout <= 0 {
+				return conn, err
+			}
+			return &idleTimeoutConn{Conn: conn, timeout: settings.IdleTimeout}, nil
+		},
+		TLSHandshakeTimeout:   settings.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: settings.ResponseHeaderTimeout,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConns:          10,
+		ForceAttemptHTTP2:     true,
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+// idleTimeoutConn fails a read when no data arrives within timeout,
+// which detects stalled transfers without limiting total download time
+type idleTimeoutConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *idleTimeoutConn) Read(b []byte) (int, error) {
+	if err := c.Conn.SetReadDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(b)
+}
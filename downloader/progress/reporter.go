@@ -0,0 +1,247 @@
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Phases reported by the downloader
+const (
+	PhaseDownload = "download"
+	PhaseExtract  = "extract"
+)
+
+// Reporter receives progress updates for long-running phases (download, extraction)
+type Reporter interface {
+	// Start begins a new phase; total is the expected number of bytes (0 or less if unknown)
+	Start(phase string, total int64)
+	// Add records n more processed bytes
+	Add(n int64)
+	// Finish ends the current phase
+	Finish()
+}
+
+// Event is a progress snapshot, emitted as one NDJSON line by the JSON reporter
+type Event struct {
+	Type           string  `json:"type"`  // Always "progress"
+	Phase          string  `json:"phase"` // download or extract
+	State          string  `json:"state"` // start, update or done
+	Current        int64   `json:"current"`
+	Total          int64   `json:"total,omitempty"`
+	Percent        float64 `json:"percent,omitempty"`
+	BytesPerSecond float64 `json:"bytes_per_second"`
+	ETASeconds     float64 `json:"eta_seconds,omitempty"`
+	Timestamp      string  `json:"timestamp"`
+}
+
+// noopReporter discards all progress updates
+type noopReporter struct{}
+
+// NewNoopReporter returns a Reporter that ignores all updates
+func NewNoopReporter() Reporter {
+	return noopReporter{}
+}
+
+func (noopReporter) Start(string, int64) {}
+func (noopReporter) Add(int64)           {}
+func (noopReporter) Finish()             {}
+
+// tracker holds the counters shared by the concrete reporters
+type tracker struct {
+	phase    string
+	total    int64
+	current  int64
+	started  time.Time
+	lastEmit time.Time
+	interval time.Duration
+}
+
+func (t *tracker) start(phase string, total int64) {
+	t.phase = phase
+	t.total = total
+	t.current = 0
+	t.started = time.Now()
+	t.lastEmit = time.Time{}
+}
+
+// due reports whether enough time passed since the last emitted update
+func (t *tracker) due() bool {
+	now := time.Now()
+	if now.Sub(t.lastEmit) < t.interval {
+		return false
+	}
+	t.lastEmit = now
+	return true
+}
+
+func (t *tracker) event(state string) Event {
+	e := Event{
+		Type:      "progress",
+		Phase:     t.phase,
+		State:     state,
+		Current:   t.current,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	if elapsed := time.Since(t.started).Seconds(); elapsed > 0 {
+		e.BytesPerSecond = float64(t.current) / elapsed
+	}
+	if t.total > 0 {
+		e.Total = t.total
+		e.Percent = float64(t.current) * 100 / float64(t.total)
+		if e.BytesPerSecond > 0 && t.current < t.total {
+			e.ETASeconds = float64(t.total-t.current) / e.BytesPerSecond
+		}
+	}
+	return e
+}
+
+// JSONReporter emits progress events as newline-delimited JSON
+type JSONReporter struct {
+	tracker
+	out io.Writer
+}
+
+// NewJSONReporter creates a reporter writing NDJSON events to out
+func NewJSONReporter(out io.Writer) *JSONReporter {
+	return &JSONReporter{
+		tracker: tracker{interval: 250 * time.Millisecond},
+		out:     out,
+	}
+}
+
+func (r *JSONReporter) Start(phase string, total int64) {
+	r.start(phase, total)
+	r.emit("start")
+}
+
+func (r *JSONReporter) Add(n int64) {
+	r.current += n
+	if r.due() {
+		r.emit("update")
+	}
+}
+
+func (r *JSONReporter) Finish() {
+	r.emit("done")
+}
+
+func (r *JSONReporter) emit(state string) {
+	data, err := json.Marshal(r.event(state))
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(r.out, string(data))
+}
+
+// BarReporter renders a single-line progress bar on an interactive terminal
+type BarReporter struct {
+	tracker
+	out   io.Writer
+	width int
+}
+
+// NewBarReporter creates a reporter drawing a progress bar on out (typically os.Stderr)
+func NewBarReporter(out io.Writer) *BarReporter {
+	return &BarReporter{
+		tracker: tracker{interval: 100 * time.Millisecond},
+		out:     out,
+		width:   30,
+	}
+}
+
+func (r *BarReporter) Start(phase string, total int64) {
+	r.start(phase, total)
+	r.render()
+}
+
+func (r *BarReporter) Add(n int64) {
+	r.current += n
+	if r.due() {
+		r.render()
+	}
+}
+
+func (r *BarReporter) Finish() {
+	r.render()
+	fmt.Fprintln(r.out)
+}
+
+func (r *BarReporter) render() {
+	e := r.event("update")
+
+	label := "⬇️  Downloading"
+	if r.phase == PhaseExtract {
+		label = "📦 Extracting "
+	}
+
+	if e.Total <= 0 {
+		fmt.Fprintf(r.out, "\r%s  %s  %s/s\033[K", label, FormatBytes(e.Current), FormatBytes(int64(e.BytesPerSecond)))
+		return
+	}
+
+	filled := int(e.Percent / 100 * float64(r.width))
+	if filled > r.width {
+		filled = r.width
+	}
+	bar := strings.Repeat("=", filled)
+	if filled < r.width {
+		bar += ">" + strings.Repeat(" ", r.width-filled-1)
+	}
+
+	eta := "--"
+	if e.ETASeconds > 0 {
+		eta = (time.Duration(e.ETASeconds) * time.Second).String()
+	} else if e.Current >= e.Total {
+		eta = "0s"
+	}
+
+	fmt.Fprintf(r.out, "\r%s [%s] %3.0f%%  %s/%s  %s/s  ETA %s\033[K",
+		label, bar, e.Percent, FormatBytes(e.Current), FormatBytes(e.Total), FormatBytes(int64(e.BytesPerSecond)), eta)
+}
+
+// FormatBytes renders a byte count with a binary unit (e.g. "12.3 MiB")
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// IsTerminal reports whether f is an interactive terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// reader reports every successful read to a Reporter
+type reader struct {
+	r        io.Reader
+	reporter Reporter
+}
+
+// NewReader wraps r so that bytes read are reported to reporter
+func NewReader(r io.Reader, reporter Reporter) io.Reader {
+	return &reader{r: r, reporter: reporter}
+}
+
+func (pr *reader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	if n > 0 {
+		pr.reporter.Add(int64(n))
+	}
+	return n, err
+}
@@ -0,0 +1,138 @@
+package downloader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strigo/logging"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// stagingPrefix marks in-progress installations living next to their final location
+const stagingPrefix = ".strigo-staging-"
+
+// stagingPIDFile records the process owning a staging directory
+const stagingPIDFile = ".strigo-staging.pid"
+
+// Staging is a temporary sibling directory where an SDK is extracted and prepared.
+// It is renamed to the final installation path only once everything succeeded,
+// so an interrupted install never looks installed.
+type Staging struct {
+	path        string
+	installPath string
+}
+
+// NewStaging creates a staging directory for installPath
+func NewStaging(installPath string) (*Staging, error) {
+	parent := filepath.Dir(installPath)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create installation directory: %w", err)
+	}
+
+	path := filepath.Join(parent, fmt.Sprintf("%s%s-%d", stagingPrefix, filepath.Base(installPath), os.Getpid()))
+	if err := os.RemoveAll(path); err != nil {
+		return nil, fmt.Errorf("failed to reset staging directory: %w", err)
+	}
+	if err := os.Mkdir(path, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	pid := []byte(strconv.Itoa(os.Getpid()))
+	if err := os.WriteFile(filepath.Join(path, stagingPIDFile), pid, 0644); err != nil {
+		os.RemoveAll(path)
+		return nil, fmt.Errorf("failed to write staging marker: %w", err)
+	}
+
+	logging.LogDebug("🚧 Staging installation in %s", path)
+	return &Staging{path: path, installPath: installPath}, nil
+}
+
+// Path returns the staging directory
+func (s *Staging) Path() string {
+	return s.path
+}
+
+// Commit atomically moves the staged installation to its final location
+func (s *Staging) Commit() error {
+	if err := os.Remove(filepath.Join(s.path, stagingPIDFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove staging marker: %w", err)
+	}
+
+	if err := os.Rename(s.path, s.installPath); err != nil {
+		if errors.Is(err, os.ErrExist) || errors.Is(err, unix.ENOTEMPTY) {
+			return fmt.Errorf("%s already exists", s.installPath)
+		}
+		return fmt.Errorf("failed to move installation into place: %w", err)
+	}
+
+	logging.LogDebug("✅ Moved staged installation to %s", s.installPath)
+	return nil
+}
+
+// Abort discards the staging directory
+func (s *Staging) Abort() {
+	if err := os.RemoveAll(s.path); err != nil {
+		logging.LogDebug("⚠️  Failed to remove staging directory %s: %v", s.path, err)
+	}
+}
+
+// IsStagingDir reports whether a directory name belongs to an in-progress installation
+func IsStagingDir(name string) bool {
+	return strings.HasPrefix(name, stagingPrefix)
+}
+
+// CleanStaleStaging removes staging directories in dir left behind by installations
+// that crashed or were interrupted. Directories owned by a running process are kept.
+func CleanStaleStaging(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if !entry.IsDir() || !IsStagingDir(entry.Name()) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if stagingOwnerAlive(path) {
+			logging.LogDebug("🚧 Staging directory %s belongs to a running install, keeping it", path)
+			continue
+		}
+
+		logging.LogInfo("🧹 Removing stale staging directory from an interrupted install: %s", path)
+		if err := os.RemoveAll(path); err != nil {
+			return removed, fmt.Errorf("failed to remove stale staging directory %s: %w", path, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// stagingOwnerAlive reports whether the process that created a staging directory is still running
+func stagingOwnerAlive(path string) bool {
+	data, err := os.ReadFile(filepath.Join(path, stagingPIDFile))
+	if err != nil {
+		return false
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return false
+	}
+	if pid == os.Getpid() {
+		// Left over by a previous process that had the same PID
+		return false
+	}
+
+	err = unix.Kill(pid, 0)
+	return err == nil || errors.Is(err, unix.EPERM)
+}
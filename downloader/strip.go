@@ -0,0 +1,137 @@
+package downloader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strigo/config"
+	"strigo/logging"
+	"strings"
+)
+
+// splitEntryName splits an archive entry name into its path components,
+// ignoring "./" prefixes and empty or "." components
+func splitEntryName(name string) []string {
+	var parts []string
+	for _, part := range strings.Split(name, "/") {
+		if part == "" || part == "." {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// stripEntryName removes the first n components of an entry name.
+// It returns false when nothing is left of the entry.
+func stripEntryName(name string, n int) (string, bool) {
+	if n <= 0 {
+		return name, true
+	}
+	parts := splitEntryName(name)
+	if len(parts) <= n {
+		return "", false
+	}
+	return strings.Join(parts[n:], "/"), true
+}
+
+// topLevelScan detects whether all entries of an archive live in a single top-level directory
+type topLevelScan struct {
+	top       string
+	directory bool // top is known to be a directory
+	shared    bool
+	symlinks  []string // Extracted symlinks, checked again before stripping top
+}
+
+func newTopLevelScan() *topLevelScan {
+	return &topLevelScan{shared: true}
+}
+
+func (s *topLevelScan) add(name string, isDir bool) {
+	parts := splitEntryName(name)
+	if len(parts) == 0 || !s.shared {
+		return
+	}
+	if s.top == "" {
+		s.top = parts[0]
+	} else if parts[0] != s.top {
+		s.shared = false
+		return
+	}
+	if len(parts) > 1 || isDir {
+		s.directory = true
+	}
+}
+
+// stripCount returns 1 when a single common top-level directory was found, 0 otherwise
+func (s *topLevelScan) stripCount() int {
+	if s.shared && s.directory && s.top != "" {
+		logging.LogDebug("✂️  Stripping common top-level directory %s", s.top)
+		return 1
+	}
+	logging.LogDebug("✂️  No single top-level directory, extracting as is")
+	return 0
+}
+
+// resolveStrip turns the configured strip setting into a component count, scanning the archive for auto
+func (e *Extractor) resolveStrip(scan func() (int, error)) (int, error) {
+	if e.stripComponents == config.StripAuto {
+		return scan()
+	}
+	return int(e.stripComponents), nil
+}
+
+// hoistTopLevel moves the content of the top-level directory top of destPath up into destPath,
+// which gives the same tree as extracting with one stripped component. symlinks are the
+// symlinks extracted below top: like when stripping during extraction, they must not point
+// outside of top (top itself is fine), as they would point outside of destPath once moved.
+func hoistTopLevel(destPath, top string, symlinks []string) error {
+	topPath := filepath.Join(destPath, top)
+	if info, err := os.Lstat(topPath); err != nil || !info.IsDir() {
+		return fmt.Errorf("failed to strip %s: not a directory", top)
+	}
+	realTop, err := filepath.EvalSymlinks(topPath)
+	if err != nil {
+		return fmt.Errorf("failed to strip %s: %w", top, err)
+	}
+	for _, link := range symlinks {
+		linkname, err := os.Readlink(link)
+		if err != nil {
+			return fmt.Errorf("failed to strip %s: %w", top, err)
+		}
+		realParent, err := filepath.EvalSymlinks(filepath.Dir(link))
+		if err != nil {
+			return fmt.Errorf("failed to strip %s: %w", top, err)
+		}
+		resolved, err := resolveLink(realParent, linkname)
+		if err != nil || (resolved != realTop && !strings.HasPrefix(resolved, realTop+string(os.PathSeparator))) {
+			return fmt.Errorf("symlink %s points outside destination: %s", link, linkname)
+		}
+	}
+
+	tmp, err := os.MkdirTemp(destPath, ".strigo-strip-")
+	if err != nil {
+		return fmt.Errorf("failed to strip %s: %w", top, err)
+	}
+	defer os.RemoveAll(tmp)
+
+	// Move the directory aside first, it may contain an entry of the same name
+	moved := filepath.Join(tmp, top)
+	if err := os.Rename(topPath, moved); err != nil {
+		return fmt.Errorf("failed to strip %s: %w", top, err)
+	}
+	entries, err := os.ReadDir(moved)
+	if err != nil {
+		return fmt.Errorf("failed to strip %s: %w", top, err)
+	}
+	for _, entry := range entries {
+		target := filepath.Join(destPath, entry.Name())
+		if _, err := os.Lstat(target); err == nil {
+			return fmt.Errorf("failed to strip %s: %s already exists", top, target)
+		}
+		if err := os.Rename(filepath.Join(moved, entry.Name()), target); err != nil {
+			return fmt.Errorf("failed to strip %s: %w", top, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strigo/config"
+	"strigo/downloader/network"
+	"strigo/logging"
+	"strings"
+)
+
+// adoptiumPageSize is the number of releases requested per feature_releases page,
+// the maximum the API accepts
+const adoptiumPageSize = 20
+
+// leadingNumber matches the feature version at the start of a version filter
+var leadingNumber = regexp.MustCompile(`^(\d+)`)
+
+// AdoptiumClient implements RepositoryClient for the Adoptium API v3
+// (api.adoptium.net or a compatible mirror)
+type AdoptiumClient struct {
+	os   string // Adoptium operating system name
+	arch string // Adoptium architecture name
+}
+
+// NewAdoptiumClient creates a new AdoptiumClient selecting binaries for the host OS and architecture
+func NewAdoptiumClient() *AdoptiumClient {
+	return NewAdoptiumClientForPlatform(HostPlatform())
+}
+
+// NewAdoptiumClientForPlatform creates a new AdoptiumClient selecting binaries for a platform
+func NewAdoptiumClientForPlatform(platform Platform) *AdoptiumClient {
+	return &AdoptiumClient{
+		os:   adoptiumOS(platform.OS),
+		arch: adoptiumArch(platform.Arch),
+	}
+}
+
+// AdoptiumRelease represents a release returned by /v3/assets/feature_releases
+type AdoptiumRelease struct {
+	ReleaseName string           `json:"release_name"`
+	ReleaseType string           `json:"release_type"`
+	Binaries    []AdoptiumBinary `json:"binaries"`
+}
+
+// AdoptiumBinary represents a binary of an Adoptium release
+type AdoptiumBinary struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	ImageType    string `json:"image_type"`
+	Package      struct {
+		Name     string `json:"name"`
+		Link     string `json:"link"`
+		Checksum string `json:"checksum"` // SHA-256
+		Size     int64  `json:"size"`
+	} `json:"package"`
+}
+
+// adoptiumReleases represents the /v3/info/available_releases response
+type adoptiumReleases struct {
+	AvailableReleases    []int `json:"available_releases"`
+	AvailableLTSReleases []int `json:"available_lts_releases"`
+}
+
+// GetAvailableVersions lists the releases of every feature version from the Adoptium API
+// and keeps the binaries built for the host. registry.APIURL is the API base URL, without
+// /v3, e.g. https://api.adoptium.net
+func (c *AdoptiumClient) GetAvailableVersions(repo config.SDKRepository, registry config.Registry, versionFilter string) ([]SDKAsset, error) {
+	baseURL := strings.TrimSuffix(registry.APIURL, "/")
+
+	settings, err := network.SettingsFromRegistry(registry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network settings: %w", err)
+	}
+	client := network.NewClientWithSettings(registry.Username, registry.Password, settings)
+
+	var info adoptiumReleases
+	if _, err := getJSON(client, baseURL+"/v3/info/available_releases", &info); err != nil {
+		return nil, fmt.Errorf("failed to query Adoptium API: %v", err)
+	}
+	logging.LogDebug("📦 Adoptium feature releases: %v (LTS: %v)", info.AvailableReleases, info.AvailableLTSReleases)
+
+	lts := make(map[int]bool)
+	for _, feature := range info.AvailableLTSReleases {
+		lts[feature] = true
+	}
+
+	// A filter starting with a feature version ("17", "21.0.5_11") only needs that feature
+	features := info.AvailableReleases
+	if m := leadingNumber.FindStringSubmatch(versionFilter); m != nil {
+		feature, _ := strconv.Atoi(m[1])
+		for _, available := range info.AvailableReleases {
+			if available == feature {
+				features = []int{feature}
+				break
+			}
+		}
+	}
+
+	imageType := repo.ImageType
+	if imageType == "" {
+		imageType = "jdk"
+	}
+	releaseType := repo.ReleaseType
+	if releaseType == "" {
+		releaseType = "ga"
+	}
+
+	var sdkAssets []SDKAsset
+	seenVersions := make(map[string]bool)
+	for _, feature := range features {
+		releases, err := c.featureReleases(client, baseURL, feature, releaseType, imageType)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, release := range releases {
+			versionName := temurinVersion(release.ReleaseName)
+			if seenVersions[versionName] {
+				continue
+			}
+			for _, binary := range release.Binaries {
+				if binary.OS != c.os || binary.Architecture != c.arch || binary.ImageType != imageType || binary.Package.Link == "" {
+					continue
+				}
+				seenVersions[versionName] = true
+				sdkAsset := SDKAsset{
+					Version:     versionName,
+					DownloadUrl: binary.Package.Link,
+					Filename:    versionName,
+					Size:        binary.Package.Size,
+					ReleaseType: release.ReleaseType,
+					LTS:         lts[feature],
+					OS:          NormalizeOS(binary.OS),
+					Arch:        NormalizeArch(binary.Architecture),
+					ImageType:   binary.ImageType,
+				}
+				if binary.Package.Checksum != "" {
+					sdkAsset.Checksum = binary.Package.Checksum
+					sdkAsset.ChecksumAlgorithm = "sha256"
+				}
+				sdkAssets = append(sdkAssets, sdkAsset)
+				break
+			}
+		}
+	}
+
+	return finalizeAssets(sdkAssets, baseURL, versionFilter)
+}
+
+// featureReleases pages through the releases of a feature version for the client's platform
+func (c *AdoptiumClient) featureReleases(client *network.Client, baseURL string, feature int, releaseType, imageType string) ([]AdoptiumRelease, error) {
+	var releases []AdoptiumRelease
+	for page := 0; ; page++ {
+		query := url.Values{}
+		query.Set("os", c.os)
+		query.Set("architecture", c.arch)
+		query.Set("image_type", imageType)
+		query.Set("jvm_impl", "hotspot")
+		query.Set("page", strconv.Itoa(page))
+		query.Set("page_size", strconv.Itoa(adoptiumPageSize))
+		query.Set("sort_order", "DESC")
+		requestURL := fmt.Sprintf("%s/v3/assets/feature_releases/%d/%s?%s", baseURL, feature, url.PathEscape(releaseType), query.Encode())
+		logging.LogDebug("🔍 Adoptium API URL: %s", requestURL)
+
+		var data []AdoptiumRelease
+		status, err := getJSON(client, requestURL, &data)
+		if status == http.StatusNotFound {
+			// Past the last page, or no release of this type for the feature
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to query Adoptium API: %v", err)
+		}
+
+		releases = append(releases, data...)
+		if len(data) < adoptiumPageSize {
+			break
+		}
+	}
+	logging.LogDebug("📦 Received %d releases for feature %d", len(releases), feature)
+	return releases, nil
+}
+
+// getJSON fetches a URL and decodes its JSON body into v. It returns the HTTP status,
+// and an error for any status other than 200.
+func getJSON(client *network.Client, requestURL string, v interface{}) (int, error) {
+	resp, err := client.Do("GET", requestURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("%s returned %d", requestURL, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to decode JSON response: %v", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// temurinVersion converts an Adoptium release name to the version found in Temurin
+// file names: "jdk-21.0.5+11" → "21.0.5_11", "jdk8u432-b06" → "8u432b06"
+func temurinVersion(releaseName string) string {
+	v := strings.TrimPrefix(strings.TrimPrefix(releaseName, "jdk"), "-")
+	v = strings.Replace(v, "-b", "b", 1)
+	return strings.ReplaceAll(v, "+", "_")
+}
+
+// adoptiumOS maps a GOOS value to its Adoptium name
+func adoptiumOS(goos string) string {
+	switch goos {
+	case "darwin":
+		return "mac"
+	default:
+		return goos
+	}
+}
+
+// adoptiumArch maps a GOARCH value to its Adoptium name
+func adoptiumArch(goarch string) string {
+	switch goarch {
+	case "amd64":
+		return "x64"
+	case "arm64":
+		return "aarch64"
+	case "386":
+		return "x86"
+	default:
+		return goarch
+	}
+}
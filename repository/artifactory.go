@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strigo/config"
+	"strigo/downloader/network"
+	"strigo/logging"
+	"strigo/repository/version"
+	"strings"
+)
+
+// ArtifactoryClient implements RepositoryClient for JFrog Artifactory repositories
+type ArtifactoryClient struct {
+	parser *version.Parser
+}
+
+// NewArtifactoryClientWithConfig creates a new ArtifactoryClient with a custom patterns file path
+// patternsFilePath can be empty to use default (strigopatterns.toml)
+func NewArtifactoryClientWithConfig(patternsFilePath string) (*ArtifactoryClient, error) {
+	parser, err := version.NewParser(patternsFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize version parser: %w", err)
+	}
+
+	return &ArtifactoryClient{
+		parser: parser,
+	}, nil
+}
+
+// ArtifactoryFile represents a file returned by the Artifactory storage list API
+type ArtifactoryFile struct {
+	URI          string `json:"uri"` // Relative to the listed folder, starting with "/"
+	Size         int64  `json:"size"`
+	LastModified string `json:"lastModified"`
+	Folder       bool   `json:"folder"`
+	SHA1         string `json:"sha1"`
+	SHA2         string `json:"sha2"` // SHA-256
+}
+
+// GetAvailableVersions lists the files under repo.Path with the storage API
+// (/api/storage/<repo>/<path>?list&deep=1) and extracts their versions.
+// registry.APIURL is the Artifactory base URL, e.g. https://artifactory.example.com/artifactory
+func (c *ArtifactoryClient) GetAvailableVersions(repo config.SDKRepository, registry config.Registry, versionFilter string) ([]SDKAsset, error) {
+	baseURL := strings.TrimSuffix(registry.APIURL, "/")
+	folder := strings.Trim(repo.Path, "/")
+
+	listURL := fmt.Sprintf("%s/api/storage/%s", baseURL, url.PathEscape(repo.Repository))
+	if folder != "" {
+		listURL += "/" + escapePath(folder)
+	}
+	listURL += "?list&deep=1&listFolders=0"
+	logging.LogDebug("🔍 Artifactory API URL: %s", listURL)
+
+	settings, err := network.SettingsFromRegistry(registry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network settings: %w", err)
+	}
+	client := network.NewClientWithSettings(registry.Username, registry.Password, settings)
+
+	resp, err := client.Do("GET", listURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Artifactory API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("artifactory API returned %d: Check if the path %s exists in repository %s", resp.StatusCode, repo.Path, repo.Repository)
+	}
+
+	var data struct {
+		Files []ArtifactoryFile `json:"files"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON response: %v", err)
+	}
+	logging.LogDebug("📦 Received %d files from Artifactory", len(data.Files))
+
+	prefix := ""
+	if folder != "" {
+		prefix = "/" + folder
+	}
+
+	candidates := make([]assetCandidate, 0, len(data.Files))
+	for _, file := range data.Files {
+		if file.Folder {
+			continue
+		}
+		path := prefix + file.URI
+		candidates = append(candidates, assetCandidate{
+			Path:         path,
+			DownloadURL:  fmt.Sprintf("%s/%s%s", baseURL, url.PathEscape(repo.Repository), escapePath(path)),
+			Checksums:    map[string]string{"sha256": file.SHA2, "sha1": file.SHA1},
+			Size:         file.Size,
+			LastModified: file.LastModified,
+		})
+	}
+
+	return buildAssets(c.parser, repo, candidates, versionFilter)
+}
+
+// escapePath escapes each segment of a slash-separated path
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
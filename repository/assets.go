@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strigo/config"
+	"strigo/downloader/network"
+	"strigo/logging"
+	"strigo/repository/version"
+	"strings"
+)
+
+// assetCandidate is a file listed by a registry, before version extraction
+type assetCandidate struct {
+	Path        string            // Path of the file in the repository, starting with "/"
+	DownloadURL string            // Absolute URL of the file
+	Checksums   map[string]string // Algorithm → hex digest, as published by the registry
+	Size        int64             // Size in bytes, 0 if unknown
+
+	LastModified string // RFC 3339 modification time, empty if unknown
+	ContentType  string // MIME type, empty if unknown
+}
+
+// buildAssets turns registry files under repo.Path into SDK assets: one per extracted
+// version, variant and platform, filtered by versionFilter and sorted from newest to oldest
+func buildAssets(parser *version.Parser, repo config.SDKRepository, candidates []assetCandidate, versionFilter string) ([]SDKAsset, error) {
+	var sdkAssets []SDKAsset
+	var ignoredFiles []string
+	seenVersions := make(map[string]bool) // To track already seen versions, variants and platforms
+
+	// Build full path for distribution
+	distributionPath := repo.Path
+	logging.LogDebug("Looking for distribution path: %s", distributionPath)
+
+	// Normalize path prefix for matching
+	// Ensure it starts with "/" and doesn't end with "/"
+	pathPrefix := "/" + strings.TrimPrefix(distributionPath, "/")
+	if !strings.HasSuffix(pathPrefix, "/") {
+		pathPrefix = pathPrefix + "/"
+	}
+
+	for _, item := range candidates {
+		logging.LogDebug("   Path: %s", item.Path)
+
+		// Check if the path starts with the requested distribution path
+		// This ensures exact prefix matching (e.g., "/jdk/adoptium/temurin/" matches
+		// "/jdk/adoptium/temurin/17/..." but NOT "/jdk/adoptium/temurin-test/...")
+		if distributionPath != "" && !strings.HasPrefix(item.Path, pathPrefix) {
+			logging.LogDebug("   Ignoring file: path does not start with %s", pathPrefix)
+			ignoredFiles = append(ignoredFiles, item.Path)
+			continue
+		}
+
+		// Use the parser to extract the version and platform
+		match, err := parser.MatchByType(item.Path, repo.Type)
+		if err != nil {
+			logging.LogDebug("   No version extracted: %v", err)
+			ignoredFiles = append(ignoredFiles, item.Path)
+			continue
+		}
+		versionName := match.Version
+
+		logging.LogDebug("   Extracted version: %s from path: %s (pattern: %s)", versionName, item.Path, match.Pattern)
+
+		// Keep one file per version, variant and platform
+		sdkAsset := SDKAsset{
+			Version:      versionName,
+			DownloadUrl:  item.DownloadURL,
+			Filename:     versionName,
+			OS:           NormalizeOS(match.OS),
+			Arch:         NormalizeArch(match.Arch),
+			Build:        match.Build,
+			Variant:      match.Variant,
+			Size:         item.Size,
+			LastModified: item.LastModified,
+			ContentType:  item.ContentType,
+		}
+		if sdkAsset.Variant == "" && IsMuslOS(match.OS) {
+			sdkAsset.Variant = "musl"
+		}
+		key := sdkAsset.Name() + "|" + sdkAsset.OS + "|" + sdkAsset.Arch
+		if !seenVersions[key] {
+			seenVersions[key] = true
+			sdkAsset.ChecksumAlgorithm, sdkAsset.Checksum = PreferredChecksum(item.Checksums)
+			sdkAssets = append(sdkAssets, sdkAsset)
+		}
+	}
+
+	if len(ignoredFiles) > 0 {
+		logging.LogDebug("❌ Ignored files:")
+		for _, f := range ignoredFiles {
+			logging.LogDebug("   - %s", f)
+		}
+	}
+
+	return finalizeAssets(sdkAssets, repo.Path, versionFilter)
+}
+
+// finalizeAssets keeps the assets matching versionFilter and sorts them from newest to oldest.
+// location names the listed path or coordinates in error messages.
+func finalizeAssets(sdkAssets []SDKAsset, location string, versionFilter string) ([]SDKAsset, error) {
+	// Filter versions if a filter is specified
+	if versionFilter != "" {
+		var filteredAssets []SDKAsset
+		for _, asset := range sdkAssets {
+			if strings.Contains(asset.Version, versionFilter) {
+				filteredAssets = append(filteredAssets, asset)
+			}
+		}
+		sdkAssets = filteredAssets
+	}
+
+	if len(sdkAssets) == 0 {
+		if versionFilter != "" {
+			return nil, fmt.Errorf("no version %s found for %s", versionFilter, location)
+		}
+		return nil, fmt.Errorf("no versions found for %s", location)
+	}
+
+	// Sort versions, plain builds before their variants
+	sort.Slice(sdkAssets, func(i, j int) bool {
+		if sdkAssets[i].Version == sdkAssets[j].Version {
+			return sdkAssets[i].Variant < sdkAssets[j].Variant
+		}
+		return version.CompareVersions(sdkAssets[j].Version, sdkAssets[i].Version)
+	})
+
+	return sdkAssets, nil
+}
+
+// ResolveChecksum fetches the expected digest of an asset from its checksum sidecar URLs,
+// trying the strongest algorithm first, or from its Disco package info. The asset is left unchanged when it already has a
+// checksum or when no sidecar is published.
+func ResolveChecksum(asset *SDKAsset, registry config.Registry) error {
+	if asset.Checksum != "" || (len(asset.ChecksumURLs) == 0 && asset.PackageInfoURL == "") {
+		return nil
+	}
+
+	settings, err := network.SettingsFromRegistry(registry)
+	if err != nil {
+		return fmt.Errorf("invalid network settings: %w", err)
+	}
+	client := network.NewClientWithSettings(registry.Username, registry.Password, settings)
+
+	if len(asset.ChecksumURLs) == 0 {
+		if err := fetchPackageInfo(client, asset); err != nil {
+			return err
+		}
+		if asset.Checksum != "" {
+			return nil
+		}
+	}
+
+	for _, algorithm := range checksumPreference {
+		checksumURL := asset.ChecksumURLs[algorithm]
+		if checksumURL == "" {
+			continue
+		}
+		logging.LogDebug("🔍 Fetching %s checksum: %s", algorithm, checksumURL)
+
+		resp, err := client.Do("GET", checksumURL, nil)
+		if err != nil {
+			return fmt.Errorf("failed to fetch checksum: %v", err)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read checksum: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			logging.LogDebug("   No %s checksum (HTTP %d)", algorithm, resp.StatusCode)
+			continue
+		}
+
+		// Sidecars contain the digest alone or in the sha256sum format ("<digest>  <name>")
+		if fields := strings.Fields(string(data)); len(fields) > 0 {
+			asset.Checksum = fields[0]
+			asset.ChecksumAlgorithm = algorithm
+			return nil
+		}
+	}
+
+	logging.LogDebug("⚠️  No checksum sidecar found for %s", asset.DownloadUrl)
+	return nil
+}
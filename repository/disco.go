@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"fmt"
+	"net/url"
+	"strigo/config"
+	"strigo/downloader/network"
+	"strigo/logging"
+	"strings"
+)
+
+// discoArchiveTypes lists the archive types requested from Disco, in order of preference
+var discoArchiveTypes = []string{"tar.gz", "zip"}
+
+// discoDistributions maps common distribution names to Disco distribution identifiers.
+// Names not listed are sent as is, with dashes replaced by underscores.
+var discoDistributions = map[string]string{
+	"adoptium":       "temurin",
+	"graalvm":        "graalvm_community",
+	"openjdk":        "oracle_open_jdk",
+	"oracle-openjdk": "oracle_open_jdk",
+	"sapmachine":     "sap_machine",
+	"ibm-semeru":     "semeru",
+}
+
+// DiscoClient implements RepositoryClient for the foojay Disco API v3
+// (api.foojay.io or an on-premises instance)
+type DiscoClient struct {
+	os   string // Disco operating system name
+	arch string // Disco architecture name
+}
+
+// NewDiscoClient creates a new DiscoClient selecting packages for the host OS and architecture
+func NewDiscoClient() *DiscoClient {
+	return NewDiscoClientForPlatform(HostPlatform())
+}
+
+// NewDiscoClientForPlatform creates a new DiscoClient selecting packages for a platform
+func NewDiscoClientForPlatform(platform Platform) *DiscoClient {
+	return &DiscoClient{
+		os:   discoOS(platform.OS),
+		arch: adoptiumArch(platform.Arch),
+	}
+}
+
+// DiscoPackage represents a package returned by /disco/v3.0/packages
+type DiscoPackage struct {
+	ID                   string `json:"id"`
+	ArchiveType          string `json:"archive_type"`
+	Distribution         string `json:"distribution"`
+	MajorVersion         int    `json:"major_version"`
+	JavaVersion          string `json:"java_version"`
+	DistributionVersion  string `json:"distribution_version"`
+	ReleaseStatus        string `json:"release_status"`
+	TermOfSupport        string `json:"term_of_support"` // lts, mts or sts
+	OperatingSystem      string `json:"operating_system"`
+	Architecture         string `json:"architecture"`
+	PackageType          string `json:"package_type"`
+	Filename             string `json:"filename"`
+	Size                 int64  `json:"size"`
+	DirectlyDownloadable bool   `json:"directly_downloadable"`
+	Links                struct {
+		PkgInfoURI          string `json:"pkg_info_uri"`
+		PkgDownloadRedirect string `json:"pkg_download_redirect"`
+	} `json:"links"`
+}
+
+// discoPackageInfo represents the /disco/v3.0/ids/<id> response
+type discoPackageInfo struct {
+	Result []struct {
+		DirectDownloadURI string `json:"direct_download_uri"`
+		Checksum          string `json:"checksum"`
+		ChecksumType      string `json:"checksum_type"`
+		ChecksumURI       string `json:"checksum_uri"`
+	} `json:"result"`
+}
+
+// GetAvailableVersions lists the packages of repo.Distribution, or else of the distribution named
+// like the repository, built for the client's platform from the Disco API. registry.APIURL is
+// the API base URL, without /disco, e.g. https://api.foojay.io
+func (c *DiscoClient) GetAvailableVersions(repo config.SDKRepository, registry config.Registry, versionFilter string) ([]SDKAsset, error) {
+	baseURL := strings.TrimSuffix(registry.APIURL, "/")
+	name := repo.Distribution
+	if name == "" {
+		name = repo.Name
+	}
+	distribution := DiscoDistribution(name)
+	if distribution == "" {
+		return nil, fmt.Errorf("distribution is required for disco registries")
+	}
+
+	packageType := repo.ImageType
+	if packageType == "" {
+		packageType = "jdk"
+	}
+	releaseStatus := repo.ReleaseType
+	if releaseStatus == "" {
+		releaseStatus = "ga"
+	}
+
+	query := url.Values{}
+	query.Set("distribution", distribution)
+	query.Set("operating_system", c.os)
+	query.Set("architecture", c.arch)
+	query.Set("package_type", packageType)
+	query.Set("release_status", releaseStatus)
+	query.Set("javafx_bundled", "false")
+	query.Set("directly_downloadable", "true")
+	for _, archiveType := range discoArchiveTypes {
+		query.Add("archive_type", archiveType)
+	}
+	requestURL := fmt.Sprintf("%s/disco/v3.0/packages?%s", baseURL, query.Encode())
+	logging.LogDebug("🔍 Disco API URL: %s", requestURL)
+
+	settings, err := network.SettingsFromRegistry(registry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network settings: %w", err)
+	}
+	client := network.NewClientWithSettings(registry.Username, registry.Password, settings)
+
+	var data struct {
+		Result  []DiscoPackage `json:"result"`
+		Message string         `json:"message"`
+	}
+	if _, err := getJSON(client, requestURL, &data); err != nil {
+		return nil, fmt.Errorf("failed to query Disco API: %v", err)
+	}
+	logging.LogDebug("📦 Received %d packages from Disco", len(data.Result))
+
+	// Keep one package per version, preferring the archive types in discoArchiveTypes order
+	best := make(map[string]DiscoPackage)
+	var order []string
+	for _, pkg := range data.Result {
+		if pkg.OperatingSystem != c.os || pkg.Architecture != c.arch || pkg.Links.PkgDownloadRedirect == "" {
+			continue
+		}
+		versionName := strings.ReplaceAll(pkg.JavaVersion, "+", "_")
+		current, seen := best[versionName]
+		if !seen {
+			order = append(order, versionName)
+		} else if archiveRank(pkg.ArchiveType) >= archiveRank(current.ArchiveType) {
+			continue
+		}
+		best[versionName] = pkg
+	}
+
+	sdkAssets := make([]SDKAsset, 0, len(order))
+	for _, versionName := range order {
+		pkg := best[versionName]
+		sdkAssets = append(sdkAssets, SDKAsset{
+			Version:        versionName,
+			DownloadUrl:    pkg.Links.PkgDownloadRedirect,
+			Filename:       versionName,
+			Size:           pkg.Size,
+			ReleaseType:    pkg.ReleaseStatus,
+			LTS:            pkg.TermOfSupport == "lts",
+			OS:             NormalizeOS(pkg.OperatingSystem),
+			Arch:           NormalizeArch(pkg.Architecture),
+			ImageType:      pkg.PackageType,
+			ArchiveType:    pkg.ArchiveType,
+			ArchiveName:    pkg.Filename,
+			PackageInfoURL: pkg.Links.PkgInfoURI,
+		})
+	}
+
+	return finalizeAssets(sdkAssets, distribution, versionFilter)
+}
+
+// DiscoDistribution maps a distribution name to its Disco identifier
+func DiscoDistribution(name string) string {
+	name = strings.ToLower(name)
+	if id, ok := discoDistributions[name]; ok {
+		return id
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// fetchPackageInfo reads the checksum, or the URL of its sidecar, from a Disco package info URL
+func fetchPackageInfo(client *network.Client, asset *SDKAsset) error {
+	var info discoPackageInfo
+	if _, err := getJSON(client, asset.PackageInfoURL, &info); err != nil {
+		return fmt.Errorf("failed to fetch package info: %v", err)
+	}
+	if len(info.Result) == 0 {
+		return nil
+	}
+
+	result := info.Result[0]
+	algorithm := strings.ToLower(result.ChecksumType)
+	switch {
+	case result.Checksum != "" && algorithm != "":
+		asset.Checksum = result.Checksum
+		asset.ChecksumAlgorithm = algorithm
+	case result.ChecksumURI != "" && algorithm != "":
+		asset.ChecksumURLs = map[string]string{algorithm: result.ChecksumURI}
+	}
+	return nil
+}
+
+// archiveRank returns the preference of an archive type, lower is better
+func archiveRank(archiveType string) int {
+	for i, t := range discoArchiveTypes {
+		if t == archiveType {
+			return i
+		}
+	}
+	return len(discoArchiveTypes)
+}
+
+// discoOS maps a GOOS value to its Disco name
+func discoOS(goos string) string {
+	switch goos {
+	case "darwin":
+		return "macos"
+	default:
+		return goos
+	}
+}
@@ -17,6 +17,7 @@ type RepositoryClient interface {
 // FetchAvailableVersions fetches available versions with optional JSON output control
 // opts[0]: jsonOutput (bool) - whether to suppress display output
 // opts[1]: patternsFilePath (string) - custom patterns file path (empty for default)
+// opts[2]: platform (Platform) - OS and architecture to list files for (host platform by default)
 func FetchAvailableVersions(repo config.SDKRepository, registry config.Registry, versionFilter string, opts ...interface{}) ([]SDKAsset, error) {
 	var client RepositoryClient
 
@@ -34,6 +35,12 @@ func FetchAvailableVersions(repo config.SDKRepository, registry config.Registry,
 			patternsFilePath = s
 		}
 	}
+	platform := HostPlatform()
+	if len(opts) > 2 {
+		if p, ok := opts[2].(Platform); ok {
+			platform = p
+		}
+	}
 
 	switch registry.Type {
 	case "nexus":
@@ -43,6 +50,35 @@ func FetchAvailableVersions(repo config.SDKRepository, registry config.Registry,
 			return nil, fmt.Errorf("failed to initialize Nexus client: %w", err)
 		}
 		client = nexusClient
+	case "artifactory":
+		artifactoryClient, err := NewArtifactoryClientWithConfig(patternsFilePath)
+		if err != nil {
+			logging.LogError("❌ Failed to initialize Artifactory client: %v", err)
+			return nil, fmt.Errorf("failed to initialize Artifactory client: %w", err)
+		}
+		client = artifactoryClient
+	case "filesystem":
+		filesystemClient, err := NewFilesystemClientWithConfig(patternsFilePath)
+		if err != nil {
+			logging.LogError("❌ Failed to initialize filesystem client: %v", err)
+			return nil, fmt.Errorf("failed to initialize filesystem client: %w", err)
+		}
+		client = filesystemClient
+	case "http-index":
+		indexClient, err := NewHTTPIndexClientWithConfig(patternsFilePath)
+		if err != nil {
+			logging.LogError("❌ Failed to initialize HTTP index client: %v", err)
+			return nil, fmt.Errorf("failed to initialize HTTP index client: %w", err)
+		}
+		client = indexClient
+	case "maven":
+		client = NewMavenClient()
+	case "adoptium":
+		client = NewAdoptiumClientForPlatform(platform)
+	case "disco":
+		client = NewDiscoClientForPlatform(platform)
+	case "oci":
+		client = NewOCIClient()
 	default:
 		logging.LogError("❌ Unsupported repository type: %s", registry.Type)
 		return nil, fmt.Errorf("unsupported repository type: %s", registry.Type)
@@ -53,6 +89,12 @@ func FetchAvailableVersions(repo config.SDKRepository, registry config.Registry,
 		return nil, err
 	}
 
+	// Hide the files built for other platforms
+	assets = filterPlatform(assets, platform)
+	if len(assets) == 0 {
+		return nil, fmt.Errorf("no versions found for %s on %s", repositoryLabel(repo, registry), platform)
+	}
+
 	// If not in JSON mode, display versions
 	if !jsonOutput {
 		displayVersions(assets)
@@ -61,6 +103,82 @@ func FetchAvailableVersions(repo config.SDKRepository, registry config.Registry,
 	return assets, nil
 }
 
+// repositoryLabel names a repository in messages: its path, or for registries queried without
+// one (Maven, Adoptium, Disco, OCI) its coordinates, repository or registry
+func repositoryLabel(repo config.SDKRepository, registry config.Registry) string {
+	switch {
+	case repo.Path != "":
+		return repo.Path
+	case repo.ArtifactID != "":
+		return repo.GroupID + ":" + repo.ArtifactID
+	case repo.Repository != "":
+		return repo.Repository
+	case repo.Name != "":
+		return repo.Name
+	case repo.Registry != "":
+		return repo.Registry
+	}
+	return registry.Type
+}
+
+// FetchFromRegistries fetches available versions from registries in priority order and merges
+// them. Each asset records the registry that supplied it, and the same version found in
+// lower-priority registries is kept in its Mirrors. A failing registry is skipped as long as
+// another one answers. opts are the same as for FetchAvailableVersions.
+func FetchFromRegistries(repo config.SDKRepository, registries []config.NamedRegistry, versionFilter string, opts ...interface{}) ([]SDKAsset, error) {
+	jsonOutput := false
+	if len(opts) > 0 {
+		if b, ok := opts[0].(bool); ok {
+			jsonOutput = b
+		}
+	}
+	// Display the merged list only, not each registry's
+	registryOpts := append([]interface{}{true}, opts[min(len(opts), 1):]...)
+
+	var merged []SDKAsset
+	index := make(map[string]int) // Asset name → position in merged
+	var firstErr error
+	for _, registry := range registries {
+		assets, err := FetchAvailableVersions(repo, registry.Registry, versionFilter, registryOpts...)
+		if err != nil {
+			logging.LogDebug("⚠️  Registry %s: %v", registry.Name, err)
+			if len(registries) > 1 {
+				err = fmt.Errorf("registry %s: %w", registry.Name, err)
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		for _, asset := range assets {
+			asset.Registry = registry.Name
+			if i, seen := index[asset.Name()]; seen {
+				merged[i].Mirrors = append(merged[i].Mirrors, asset)
+				continue
+			}
+			index[asset.Name()] = len(merged)
+			merged = append(merged, asset)
+		}
+	}
+
+	if len(merged) == 0 {
+		if firstErr != nil {
+			return nil, firstErr
+		}
+		return nil, fmt.Errorf("no registry configured for %s", repo.Path)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return version.CompareVersions(merged[j].Version, merged[i].Version)
+	})
+
+	if !jsonOutput {
+		displayVersions(merged)
+	}
+	return merged, nil
+}
+
 // displayVersions handles the user-friendly output
 func displayVersions(assets []SDKAsset) {
 	// Create a map to group by major version
@@ -69,7 +187,7 @@ func displayVersions(assets []SDKAsset) {
 	// Extract major version and group
 	for _, asset := range assets {
 		majorVersion := ExtractMajorVersion(asset.Version)
-		versionGroups[majorVersion] = append(versionGroups[majorVersion], asset.Version)
+		versionGroups[majorVersion] = append(versionGroups[majorVersion], asset.Name())
 	}
 
 	// Get numerically sorted major versions
@@ -88,7 +206,7 @@ func displayVersions(assets []SDKAsset) {
 
 		// Sort versions in each group
 		sort.Slice(versions, func(i, j int) bool {
-			return CompareVersions(versions[i], versions[j])
+			return version.CompareVersions(versions[i], versions[j])
 		})
 
 		logging.LogOutput("  - %s:", major)
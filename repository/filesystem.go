@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strigo/config"
+	"strigo/downloader/network"
+	"strigo/logging"
+	"strigo/repository/version"
+	"strings"
+	"time"
+)
+
+// FilesystemClient implements RepositoryClient for SDK archives stored in a local
+// or network-mounted directory
+type FilesystemClient struct {
+	parser *version.Parser
+}
+
+// NewFilesystemClientWithConfig creates a new FilesystemClient with a custom patterns file path
+// patternsFilePath can be empty to use default (strigopatterns.toml)
+func NewFilesystemClientWithConfig(patternsFilePath string) (*FilesystemClient, error) {
+	parser, err := version.NewParser(patternsFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize version parser: %w", err)
+	}
+
+	return &FilesystemClient{
+		parser: parser,
+	}, nil
+}
+
+// GetAvailableVersions walks repo.Path below the registry directory and extracts versions
+// from the relative file paths. registry.APIURL is a directory or a file:// URL and may
+// contain the {repository} placeholder.
+func (c *FilesystemClient) GetAvailableVersions(repo config.SDKRepository, registry config.Registry, versionFilter string) ([]SDKAsset, error) {
+	root := strings.ReplaceAll(registry.APIURL, "{repository}", repo.Repository)
+	if path, ok := network.LocalPath(root); ok {
+		root = path
+	}
+	root, err := config.ExpandTilde(root)
+	if err != nil {
+		return nil, err
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry directory %s: %w", registry.APIURL, err)
+	}
+
+	dir := filepath.Join(root, filepath.FromSlash(strings.Trim(repo.Path, "/")))
+	logging.LogDebug("🔍 Walking filesystem registry: %s", dir)
+
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("directory %s not found: Check if the path %s exists in the registry", dir, repo.Path)
+	}
+
+	var candidates []assetCandidate
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(entry.Name(), ".") && path != dir {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || isChecksumFile(entry.Name()) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		candidates = append(candidates, assetCandidate{
+			Path:         "/" + filepath.ToSlash(rel),
+			DownloadURL:  network.FileURL(path),
+			Checksums:    readChecksumFiles(path),
+			Size:         info.Size(),
+			LastModified: info.ModTime().UTC().Format(time.RFC3339),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", dir, err)
+	}
+	logging.LogDebug("📦 Found %d files in %s", len(candidates), dir)
+
+	return buildAssets(c.parser, repo, candidates, versionFilter)
+}
+
+// isChecksumFile reports whether a file is a checksum sidecar (e.g. jdk.tar.gz.sha256)
+func isChecksumFile(name string) bool {
+	for _, algorithm := range checksumPreference {
+		if strings.HasSuffix(name, "."+algorithm) {
+			return true
+		}
+	}
+	return false
+}
+
+// readChecksumFiles reads the checksum sidecars of a file, in the sha256sum format
+// ("<digest>  <name>") or containing the digest alone
+func readChecksumFiles(path string) map[string]string {
+	checksums := make(map[string]string)
+	for _, algorithm := range checksumPreference {
+		data, err := os.ReadFile(path + "." + algorithm)
+		if err != nil {
+			continue
+		}
+		if fields := strings.Fields(string(data)); len(fields) > 0 {
+			checksums[algorithm] = fields[0]
+		}
+	}
+	return checksums
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strigo/config"
+	"strigo/downloader/network"
+	"strigo/logging"
+	"strigo/repository/version"
+	"strings"
+)
+
+// DefaultIndexMaxDepth is the number of directory levels crawled below the repository path
+const DefaultIndexMaxDepth = 3
+
+// maxIndexPageSize bounds the size of a directory listing page
+const maxIndexPageSize = 10 << 20
+
+// hrefPattern matches the link targets of an HTML page
+var hrefPattern = regexp.MustCompile(`(?i)<a\s[^>]*?href\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+
+// HTTPIndexClient implements RepositoryClient for plain HTTP directory listings
+// (Apache/nginx autoindex pages)
+type HTTPIndexClient struct {
+	parser *version.Parser
+}
+
+// NewHTTPIndexClientWithConfig creates a new HTTPIndexClient with a custom patterns file path
+// patternsFilePath can be empty to use default (strigopatterns.toml)
+func NewHTTPIndexClientWithConfig(patternsFilePath string) (*HTTPIndexClient, error) {
+	parser, err := version.NewParser(patternsFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize version parser: %w", err)
+	}
+
+	return &HTTPIndexClient{
+		parser: parser,
+	}, nil
+}
+
+// indexCrawl holds the state of a directory listing crawl
+type indexCrawl struct {
+	client     *network.Client
+	root       *url.URL // registry root, asset paths are relative to it
+	start      *url.URL // repository path, links outside of it are not followed
+	maxDepth   int
+	visited    map[string]bool
+	candidates []assetCandidate
+}
+
+// GetAvailableVersions crawls the directory listings below repo.Path and extracts versions
+// from the linked files. registry.APIURL is the listing root and may contain {repository}.
+func (c *HTTPIndexClient) GetAvailableVersions(repo config.SDKRepository, registry config.Registry, versionFilter string) ([]SDKAsset, error) {
+	rootURL := strings.TrimSuffix(strings.ReplaceAll(registry.APIURL, "{repository}", repo.Repository), "/") + "/"
+	root, err := url.Parse(rootURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry URL %s: %w", registry.APIURL, err)
+	}
+	start := root
+	if path := strings.Trim(repo.Path, "/"); path != "" {
+		start = root.ResolveReference(&url.URL{Path: path + "/"})
+	}
+
+	settings, err := network.SettingsFromRegistry(registry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network settings: %w", err)
+	}
+
+	maxDepth := DefaultIndexMaxDepth
+	if registry.MaxDepth != nil {
+		maxDepth = *registry.MaxDepth
+	}
+
+	crawl := &indexCrawl{
+		client:   network.NewClientWithSettings(registry.Username, registry.Password, settings),
+		root:     root,
+		start:    start,
+		maxDepth: maxDepth,
+		visited:  make(map[string]bool),
+	}
+	if err := crawl.walk(start, 0); err != nil {
+		return nil, err
+	}
+	logging.LogDebug("📦 Found %d files in directory listings", len(crawl.candidates))
+
+	return buildAssets(c.parser, repo, crawl.candidates, versionFilter)
+}
+
+// walk lists a directory page and recurses into its subdirectories up to maxDepth
+func (w *indexCrawl) walk(dir *url.URL, depth int) error {
+	if w.visited[dir.String()] {
+		return nil
+	}
+	w.visited[dir.String()] = true
+	logging.LogDebug("🔍 Listing %s (depth %d)", dir, depth)
+
+	links, err := w.list(dir)
+	if err != nil {
+		if depth == 0 {
+			return err
+		}
+		logging.LogDebug("⚠️  Skipping %s: %v", dir, err)
+		return nil
+	}
+
+	for _, link := range links {
+		if !strings.HasPrefix(link.Path, w.start.Path) || link.Path == dir.Path {
+			// Parent directory, sort links or another part of the site
+			continue
+		}
+		if strings.HasSuffix(link.Path, "/") {
+			if depth < w.maxDepth {
+				if err := w.walk(link, depth+1); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if isChecksumFile(link.Path) {
+			continue
+		}
+		w.candidates = append(w.candidates, assetCandidate{
+			Path:        "/" + strings.TrimPrefix(link.Path, w.root.Path),
+			DownloadURL: link.String(),
+		})
+	}
+	return nil
+}
+
+// list fetches a directory page and returns its links resolved against the page URL,
+// keeping only those on the same host
+func (w *indexCrawl) list(dir *url.URL) ([]*url.URL, error) {
+	resp, err := w.client.Do("GET", dir.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch directory listing: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("directory listing %s returned %d: Check if the path exists", dir, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxIndexPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory listing: %v", err)
+	}
+
+	var links []*url.URL
+	seen := make(map[string]bool)
+	for _, match := range hrefPattern.FindAllStringSubmatch(string(body), -1) {
+		href := match[1] + match[2] + match[3]
+		ref, err := url.Parse(strings.TrimSpace(html.UnescapeString(href)))
+		if err != nil || ref.Path == "" {
+			// Sort links ("?C=N;O=D") and anchors
+			continue
+		}
+		link := dir.ResolveReference(ref)
+		link.RawQuery, link.Fragment = "", ""
+		if link.Scheme != dir.Scheme || link.Host != dir.Host || seen[link.String()] {
+			continue
+		}
+		seen[link.String()] = true
+		links = append(links, link)
+	}
+	return links, nil
+}
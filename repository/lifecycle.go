@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strigo/config"
+	"strigo/downloader/network"
+	"strigo/logging"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// LifecycleDefaultDistribution holds the entries applying to distributions without their own
+const LifecycleDefaultDistribution = "default"
+
+// DefaultLifecycleTTL is how long lifecycle data fetched from lifecycle_url is cached
+const DefaultLifecycleTTL = 24 * time.Hour
+
+// lifecycleDateLayout is the format of lifecycle dates
+const lifecycleDateLayout = "2006-01-02"
+
+// LifecycleEntry describes the support lifecycle of a major version
+type LifecycleEntry struct {
+	LTS        bool   `toml:"lts" json:"lts"`
+	GA         string `toml:"ga" json:"ga,omitempty"`                   // General availability date (YYYY-MM-DD)
+	SupportEnd string `toml:"support_end" json:"support_end,omitempty"` // End of active support (YYYY-MM-DD)
+	EOL        string `toml:"eol" json:"eol,omitempty"`                 // End of life, no more updates (YYYY-MM-DD)
+}
+
+// EOLDate returns the end of life date, false if unknown
+func (e LifecycleEntry) EOLDate() (time.Time, bool) {
+	eol, err := time.Parse(lifecycleDateLayout, e.EOL)
+	return eol, err == nil
+}
+
+// IsEOL reports whether the end of life date has passed at now
+func (e LifecycleEntry) IsEOL(now time.Time) bool {
+	eol, ok := e.EOLDate()
+	return ok && now.After(eol)
+}
+
+// IsSupportEnded reports whether active support ended at now
+func (e LifecycleEntry) IsSupportEnded(now time.Time) bool {
+	supportEnd, err := time.Parse(lifecycleDateLayout, e.SupportEnd)
+	return err == nil && now.After(supportEnd)
+}
+
+// Lifecycle maps SDK type → distribution → major version → lifecycle entry
+type Lifecycle map[string]map[string]map[string]LifecycleEntry
+
+// Lookup returns the lifecycle entry of a major version, falling back to the
+// default distribution of the SDK type
+func (l Lifecycle) Lookup(sdkType, distribution string, major int) (LifecycleEntry, bool) {
+	key := strconv.Itoa(major)
+	for _, dist := range []string{distribution, LifecycleDefaultDistribution} {
+		if entry, ok := l[sdkType][dist][key]; ok {
+			return entry, true
+		}
+	}
+	return LifecycleEntry{}, false
+}
+
+// ParseLifecycle parses lifecycle data in TOML or, when format is "json", JSON:
+//
+//	[jdk.default.17]
+//	lts = true
+//	eol = "2029-09-30"
+func ParseLifecycle(data []byte, format string) (Lifecycle, error) {
+	var lifecycle Lifecycle
+	var err error
+	if format == "json" {
+		err = json.Unmarshal(data, &lifecycle)
+	} else {
+		err = toml.Unmarshal(data, &lifecycle)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse lifecycle data: %w", err)
+	}
+
+	for sdkType, distributions := range lifecycle {
+		for dist, majors := range distributions {
+			for major, entry := range majors {
+				if _, err := strconv.Atoi(major); err != nil {
+					return nil, fmt.Errorf("lifecycle %s.%s: invalid major version %q", sdkType, dist, major)
+				}
+				for field, date := range map[string]string{"ga": entry.GA, "support_end": entry.SupportEnd, "eol": entry.EOL} {
+					if date == "" {
+						continue
+					}
+					if _, err := time.Parse(lifecycleDateLayout, date); err != nil {
+						return nil, fmt.Errorf("lifecycle %s.%s.%s: invalid %s %q (expected YYYY-MM-DD)", sdkType, dist, major, field, date)
+					}
+				}
+			}
+		}
+	}
+	return lifecycle, nil
+}
+
+// lifecycleFormat returns the format of a lifecycle file from its extension
+func lifecycleFormat(name string) string {
+	if strings.EqualFold(path.Ext(name), ".json") {
+		return "json"
+	}
+	return "toml"
+}
+
+// LoadLifecycle loads the lifecycle data configured in [general]. Data from lifecycle_url is
+// cached in the cache directory and refreshed after lifecycle_ttl; the stale cache, then
+// lifecycle_file, are used when the URL cannot be fetched. Returns nil when none is configured.
+func LoadLifecycle(cfg *config.Config) (Lifecycle, error) {
+	general := cfg.General
+
+	if general.LifecycleURL != "" {
+		lifecycle, err := loadRemoteLifecycle(cfg)
+		if err == nil {
+			return lifecycle, nil
+		}
+		if general.LifecycleFile == "" {
+			return nil, err
+		}
+		logging.LogDebug("⚠️  %v, using %s", err, general.LifecycleFile)
+	}
+
+	if general.LifecycleFile == "" {
+		return nil, nil
+	}
+	lifecyclePath, err := config.ExpandTilde(general.LifecycleFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(lifecyclePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lifecycle file: %w", err)
+	}
+	return ParseLifecycle(data, lifecycleFormat(lifecyclePath))
+}
+
+// loadRemoteLifecycle returns the lifecycle data of lifecycle_url, from the cache when fresh
+func loadRemoteLifecycle(cfg *config.Config) (Lifecycle, error) {
+	general := cfg.General
+	format := lifecycleFormat(general.LifecycleURL)
+
+	cacheDir, err := config.ExpandTilde(general.CacheDir)
+	if err != nil {
+		return nil, err
+	}
+	cachePath := filepath.Join(cacheDir, "lifecycle."+format)
+
+	ttl := DefaultLifecycleTTL
+	if general.LifecycleTTL != "" {
+		if ttl, err = time.ParseDuration(general.LifecycleTTL); err != nil {
+			return nil, fmt.Errorf("invalid lifecycle_ttl %q: %w", general.LifecycleTTL, err)
+		}
+	}
+
+	if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < ttl {
+		if data, err := os.ReadFile(cachePath); err == nil {
+			logging.LogDebug("📋 Using cached lifecycle data from %s", cachePath)
+			return ParseLifecycle(data, format)
+		}
+	}
+
+	data, fetchErr := fetchLifecycle(cfg)
+	if fetchErr == nil {
+		lifecycle, err := ParseLifecycle(data, format)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(cachePath, data, 0644); err != nil {
+			logging.LogDebug("⚠️  Failed to cache lifecycle data: %v", err)
+		}
+		return lifecycle, nil
+	}
+
+	// Stale data is better than none
+	if data, err := os.ReadFile(cachePath); err == nil {
+		logging.LogDebug("⚠️  %v, using cached lifecycle data", fetchErr)
+		return ParseLifecycle(data, format)
+	}
+	return nil, fetchErr
+}
+
+// fetchLifecycle downloads lifecycle_url, with the credentials of lifecycle_registry if set
+func fetchLifecycle(cfg *config.Config) ([]byte, error) {
+	var registry config.Registry
+	if name := cfg.General.LifecycleRegistry; name != "" {
+		var exists bool
+		if registry, exists = cfg.Registries[name]; !exists {
+			return nil, fmt.Errorf("lifecycle registry %s not found", name)
+		}
+	}
+
+	settings, err := network.SettingsFromRegistry(registry)
+	if err != nil {
+		return nil, err
+	}
+	client := network.NewClientWithSettings(registry.Username, registry.Password, settings)
+
+	logging.LogDebug("🔍 Fetching lifecycle data from %s", cfg.General.LifecycleURL)
+	resp, err := client.Do("GET", cfg.General.LifecycleURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch lifecycle data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("lifecycle URL returned %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lifecycle data: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strigo/config"
+	"strigo/downloader/network"
+	"strigo/logging"
+	"strings"
+)
+
+// DefaultMavenExtension is the packaging of Maven artifacts when the repository does not set one
+const DefaultMavenExtension = "tar.gz"
+
+// MavenClient implements RepositoryClient for Maven 2 layout repositories
+// (Nexus maven2, Artifactory maven, Maven Central mirrors)
+type MavenClient struct{}
+
+// NewMavenClient creates a new MavenClient. Versions come from maven-metadata.xml,
+// so no version patterns are needed.
+func NewMavenClient() *MavenClient {
+	return &MavenClient{}
+}
+
+// MavenMetadata represents the artifact-level maven-metadata.xml
+type MavenMetadata struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Versioning struct {
+		Latest   string   `xml:"latest"`
+		Release  string   `xml:"release"`
+		Versions []string `xml:"versions>version"`
+	} `xml:"versioning"`
+}
+
+// GetAvailableVersions reads the version list of repo's coordinates from maven-metadata.xml
+// and builds download URLs following the Maven layout. registry.APIURL is the repository
+// base URL and may contain {repository}, e.g. https://nexus.example.com/repository/{repository}
+func (c *MavenClient) GetAvailableVersions(repo config.SDKRepository, registry config.Registry, versionFilter string) ([]SDKAsset, error) {
+	if repo.GroupID == "" || repo.ArtifactID == "" {
+		return nil, fmt.Errorf("group_id and artifact_id are required for maven registries")
+	}
+
+	baseURL := strings.TrimSuffix(strings.ReplaceAll(registry.APIURL, "{repository}", repo.Repository), "/")
+	artifactURL := fmt.Sprintf("%s/%s/%s", baseURL, strings.ReplaceAll(repo.GroupID, ".", "/"), escapePath(repo.ArtifactID))
+	metadataURL := artifactURL + "/maven-metadata.xml"
+	logging.LogDebug("🔍 Maven metadata URL: %s", metadataURL)
+
+	settings, err := network.SettingsFromRegistry(registry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network settings: %w", err)
+	}
+	client := network.NewClientWithSettings(registry.Username, registry.Password, settings)
+
+	resp, err := client.Do("GET", metadataURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch maven-metadata.xml: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("maven repository returned %d: Check if %s:%s exists in repository %s", resp.StatusCode, repo.GroupID, repo.ArtifactID, repo.Repository)
+	}
+
+	var metadata MavenMetadata
+	if err := xml.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("failed to decode maven-metadata.xml: %v", err)
+	}
+	logging.LogDebug("📦 Received %d versions from maven-metadata.xml", len(metadata.Versioning.Versions))
+
+	extension := repo.Extension
+	if extension == "" {
+		extension = DefaultMavenExtension
+	}
+
+	var sdkAssets []SDKAsset
+	seenVersions := make(map[string]bool)
+	for _, v := range metadata.Versioning.Versions {
+		v = strings.TrimSpace(v)
+		if v == "" || seenVersions[v] {
+			continue
+		}
+		seenVersions[v] = true
+
+		filename := repo.ArtifactID + "-" + v
+		if repo.Classifier != "" {
+			filename += "-" + repo.Classifier
+		}
+		filename += "." + extension
+
+		downloadURL := fmt.Sprintf("%s/%s/%s", artifactURL, escapePath(v), escapePath(filename))
+		sdkAssets = append(sdkAssets, SDKAsset{
+			Version:     v,
+			DownloadUrl: downloadURL,
+			Filename:    v,
+			ChecksumURLs: map[string]string{
+				"sha256": downloadURL + ".sha256",
+				"sha1":   downloadURL + ".sha1",
+			},
+		})
+	}
+
+	return finalizeAssets(sdkAssets, repo.GroupID+":"+repo.ArtifactID, versionFilter)
+}
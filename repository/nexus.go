@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"sort"
 	"strigo/config"
+	"strigo/downloader/network"
 	"strigo/logging"
 	"strigo/repository/version"
 	"strings"
@@ -14,10 +14,63 @@ import (
 
 // SDKAsset represents an available version of an SDK
 type SDKAsset struct {
-	Version     string `json:"version"`
-	DownloadUrl string `json:"downloadUrl"`
-	Filename    string `json:"filename"`
-	Size        int64  `json:"size"`
+	Version           string `json:"version"`
+	DownloadUrl       string `json:"downloadUrl"`
+	Filename          string `json:"filename"`
+	Size              int64  `json:"size"`                        // Size in bytes, 0 if unknown
+	LastModified      string `json:"lastModified,omitempty"`      // RFC 3339 modification time reported by the registry
+	ContentType       string `json:"contentType,omitempty"`       // MIME type reported by the registry
+	Checksum          string `json:"checksum,omitempty"`          // Expected hex digest of the artifact
+	ChecksumAlgorithm string `json:"checksumAlgorithm,omitempty"` // sha512, sha256, sha1 or md5
+	OS                string `json:"os,omitempty"`                // GOOS name, empty if the file runs anywhere or is unknown
+	Arch              string `json:"arch,omitempty"`              // GOARCH name, empty if the file runs anywhere or is unknown
+	Build             string `json:"build,omitempty"`             // Vendor build number, such as Zulu's "21.38.21"
+	Variant           string `json:"variant,omitempty"`           // Flavour of the build: crac, fx, musl...
+
+	// Release metadata, filled by registries with a structured API (Adoptium, Disco)
+	ReleaseType string `json:"releaseType,omitempty"` // ga or ea
+	LTS         bool   `json:"lts,omitempty"`
+	ImageType   string `json:"imageType,omitempty"`   // jdk or jre
+	ArchiveType string `json:"archiveType,omitempty"` // tar.gz, zip...
+	ArchiveName string `json:"archiveName,omitempty"` // File name of the archive, when DownloadUrl does not end with it
+
+	// Algorithm → URL of a checksum sidecar, fetched by ResolveChecksum when Checksum is empty
+	ChecksumURLs map[string]string `json:"checksumUrls,omitempty"`
+
+	// Disco package details, read by ResolveChecksum to find the checksum
+	PackageInfoURL string `json:"packageInfoUrl,omitempty"`
+
+	// OCI manifest of the tag, read by ResolveManifest to find the layer blob
+	ManifestURL string `json:"manifestUrl,omitempty"`
+	BearerToken string `json:"-"` // Registry token obtained while resolving the manifest, sent with the download
+
+	// Registry chains: name of the registry that supplied the asset, and the same version
+	// in lower-priority registries, tried in order when downloading from this one fails
+	Registry string     `json:"registry,omitempty"`
+	Mirrors  []SDKAsset `json:"mirrors,omitempty"`
+}
+
+// Name identifies the asset among the builds of a distribution: its version, followed by
+// its variant if any ("21.0.5", "21.0.5-crac"). Variant builds are installed under this name.
+func (a SDKAsset) Name() string {
+	if a.Variant == "" {
+		return a.Version
+	}
+	return a.Version + "-" + a.Variant
+}
+
+// checksumPreference lists supported digest algorithms from strongest to weakest
+var checksumPreference = []string{"sha512", "sha256", "sha1", "md5"}
+
+// PreferredChecksum picks the strongest digest from a map of algorithm → hex digest.
+// Returns empty strings if no supported algorithm is present.
+func PreferredChecksum(checksums map[string]string) (algorithm string, digest string) {
+	for _, algo := range checksumPreference {
+		if value := checksums[algo]; value != "" {
+			return algo, value
+		}
+	}
+	return "", ""
 }
 
 // NexusClient implements RepositoryClient for Nexus repositories
@@ -46,18 +99,19 @@ func NewNexusClientWithConfig(patternsFilePath string) (*NexusClient, error) {
 
 // NexusAsset represents an asset returned by Nexus API
 type NexusAsset struct {
-	Path        string            `json:"path"`
-	DownloadUrl string            `json:"downloadUrl"`
-	Checksum    map[string]string `json:"checksum"`
+	Path         string            `json:"path"`
+	DownloadUrl  string            `json:"downloadUrl"`
+	Checksum     map[string]string `json:"checksum"`
+	FileSize     int64             `json:"fileSize"`
+	LastModified string            `json:"lastModified"`
+	ContentType  string            `json:"contentType"`
 }
 
 // GetAvailableVersions fetches available versions of a JDK from a Nexus repository.
-// It handles pagination using continuationToken to retrieve all assets.
+// It handles pagination using continuationToken to retrieve all assets. When the registry
+// enables search, only the assets under repo.Path are requested through the search API,
+// falling back to listing the whole repository if search is unavailable.
 func (c *NexusClient) GetAvailableVersions(repo config.SDKRepository, registry config.Registry, versionFilter string) ([]SDKAsset, error) {
-	var sdkAssets []SDKAsset
-	var ignoredFiles []string
-	seenVersions := make(map[string]bool) // To track already seen versions
-
 	// Ensure apiURL is correctly formatted and replace placeholders
 	logging.LogDebug("🔍 Registry API URL: %s", registry.APIURL)
 	logging.LogDebug("🔍 Repository: %s", repo.Repository)
@@ -66,8 +120,87 @@ func (c *NexusClient) GetAvailableVersions(repo config.SDKRepository, registry c
 	apiURL := strings.ReplaceAll(registry.APIURL, "{repository}", repo.Repository)
 	logging.LogDebug("🔍 API URL after repository replacement: %s", apiURL)
 
-	// Collect all items across all pages using pagination
+	settings, err := network.SettingsFromRegistry(registry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network settings: %w", err)
+	}
+	client := network.NewClientWithSettings(registry.Username, registry.Password, settings)
+
+	if registry.Username != "" && registry.Password != "" {
+		logging.LogDebug("🔐 Using Basic Auth with username: %s", registry.Username)
+	}
+
 	var allItems []NexusAsset
+	if registry.Search != nil && *registry.Search {
+		if searchURL, ok := nexusSearchURL(apiURL, repo.Path); ok {
+			items, status, err := fetchNexusAssets(client, searchURL)
+			switch {
+			case err == nil && len(items) > 0:
+				allItems = items
+			case err == nil:
+				logging.LogDebug("⚠️  Nexus search returned no assets, listing the repository instead")
+			case status != 0:
+				logging.LogDebug("⚠️  Nexus search unavailable (HTTP %d), listing the repository instead", status)
+			default:
+				return nil, err
+			}
+		} else {
+			logging.LogDebug("⚠️  Cannot derive a search URL from %s, listing the repository instead", apiURL)
+		}
+	}
+
+	if allItems == nil {
+		items, status, err := fetchNexusAssets(client, apiURL)
+		if err != nil {
+			if status != 0 {
+				return nil, fmt.Errorf("nexus API returned %d: Check if the path %s exists in Nexus", status, repo.Path)
+			}
+			return nil, err
+		}
+		allItems = items
+	}
+
+	// Process all collected items
+	logging.LogDebug("🔍 Processing %d total items from Nexus", len(allItems))
+
+	candidates := make([]assetCandidate, 0, len(allItems))
+	for _, item := range allItems {
+		candidates = append(candidates, assetCandidate{
+			Path:         item.Path,
+			DownloadURL:  item.DownloadUrl,
+			Checksums:    item.Checksum,
+			Size:         item.FileSize,
+			LastModified: item.LastModified,
+			ContentType:  item.ContentType,
+		})
+	}
+
+	return buildAssets(c.parser, repo, candidates, versionFilter)
+}
+
+// nexusSearchURL derives the search API URL (/v1/search/assets) from an assets listing URL,
+// matching the asset names under path with a wildcard
+func nexusSearchURL(apiURL, path string) (string, bool) {
+	path = strings.Trim(path, "/")
+	if path == "" || !strings.Contains(apiURL, "/v1/assets") {
+		return "", false
+	}
+
+	searchURL, err := url.Parse(strings.Replace(apiURL, "/v1/assets", "/v1/search/assets", 1))
+	if err != nil {
+		return "", false
+	}
+	query := searchURL.Query()
+	query.Set("name", path+"/*")
+	searchURL.RawQuery = query.Encode()
+	return searchURL.String(), true
+}
+
+// fetchNexusAssets collects the assets of every page of a Nexus listing or search URL.
+// On an unexpected HTTP status, the status is returned along with the error.
+func fetchNexusAssets(client *network.Client, apiURL string) ([]NexusAsset, int, error) {
+	// Collect all items across all pages using pagination
+	allItems := []NexusAsset{}
 	continuationToken := ""
 	pageCount := 0
 
@@ -83,30 +216,15 @@ func (c *NexusClient) GetAvailableVersions(repo config.SDKRepository, registry c
 
 		logging.LogDebug("🔍 Nexus API URL: %s", requestURL)
 
-		// Create HTTP request
-		req, err := http.NewRequest("GET", requestURL, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create HTTP request: %v", err)
-		}
-
-		// Add Basic Auth if credentials are provided
-		if registry.Username != "" && registry.Password != "" {
-			req.SetBasicAuth(registry.Username, registry.Password)
-			if pageCount == 1 {
-				logging.LogDebug("🔐 Using Basic Auth with username: %s", registry.Username)
-			}
-		}
-
-		// Execute request
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		// Execute request (with registry timeouts and retries)
+		resp, err := client.Do("GET", requestURL, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to query Nexus API: %v", err)
+			return nil, 0, fmt.Errorf("failed to query Nexus API: %v", err)
 		}
 
 		if resp.StatusCode != http.StatusOK {
 			resp.Body.Close()
-			return nil, fmt.Errorf("nexus API returned %d: Check if the path %s exists in Nexus", resp.StatusCode, repo.Path)
+			return nil, resp.StatusCode, fmt.Errorf("nexus API returned %d", resp.StatusCode)
 		}
 
 		// Parse JSON response
@@ -116,7 +234,7 @@ func (c *NexusClient) GetAvailableVersions(repo config.SDKRepository, registry c
 		}
 		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
 			resp.Body.Close()
-			return nil, fmt.Errorf("failed to decode JSON response: %v", err)
+			return nil, 0, fmt.Errorf("failed to decode JSON response: %v", err)
 		}
 		resp.Body.Close()
 
@@ -131,88 +249,7 @@ func (c *NexusClient) GetAvailableVersions(repo config.SDKRepository, registry c
 			logging.LogDebug("➡️  More pages available, continuing pagination...")
 		} else {
 			logging.LogDebug("✅ Pagination complete. Total items: %d", len(allItems))
-			break
+			return allItems, 0, nil
 		}
 	}
-
-	// Process all collected items
-	logging.LogDebug("🔍 Processing %d total items from Nexus", len(allItems))
-
-	// Build full path for distribution
-	distributionPath := repo.Path
-	logging.LogDebug("Looking for distribution path: %s", distributionPath)
-
-	// Normalize path prefix for matching
-	// Ensure it starts with "/" and doesn't end with "/"
-	pathPrefix := "/" + strings.TrimPrefix(distributionPath, "/")
-	if !strings.HasSuffix(pathPrefix, "/") {
-		pathPrefix = pathPrefix + "/"
-	}
-
-	for _, item := range allItems {
-		logging.LogDebug("   Path: %s", item.Path)
-
-		// Check if the path starts with the requested distribution path
-		// This ensures exact prefix matching (e.g., "/jdk/adoptium/temurin/" matches
-		// "/jdk/adoptium/temurin/17/..." but NOT "/jdk/adoptium/temurin-test/...")
-		if distributionPath != "" && !strings.HasPrefix(item.Path, pathPrefix) {
-			logging.LogDebug("   Ignoring file: path does not start with %s", pathPrefix)
-			ignoredFiles = append(ignoredFiles, item.Path)
-			continue
-		}
-
-		// Use the parser to extract version
-		versionName, patternName, err := c.parser.ExtractVersionByType(item.Path, repo.Type)
-		if err != nil {
-			logging.LogDebug("   No version extracted: %v", err)
-			ignoredFiles = append(ignoredFiles, item.Path)
-			continue
-		}
-
-		logging.LogDebug("   Extracted version: %s from path: %s (pattern: %s)", versionName, item.Path, patternName)
-
-		// Check if this version has already been seen
-		if !seenVersions[versionName] {
-			seenVersions[versionName] = true
-			sdkAsset := SDKAsset{
-				Version:     versionName,
-				DownloadUrl: item.DownloadUrl,
-				Filename:    versionName,
-				// Size will be added later if needed
-			}
-			sdkAssets = append(sdkAssets, sdkAsset)
-		}
-	}
-
-	if len(ignoredFiles) > 0 {
-		logging.LogDebug("❌ Ignored files:")
-		for _, f := range ignoredFiles {
-			logging.LogDebug("   - %s", f)
-		}
-	}
-
-	// Filter versions if a filter is specified
-	if versionFilter != "" {
-		var filteredAssets []SDKAsset
-		for _, asset := range sdkAssets {
-			if strings.Contains(asset.Version, versionFilter) {
-				filteredAssets = append(filteredAssets, asset)
-			}
-		}
-		sdkAssets = filteredAssets
-	}
-
-	if len(sdkAssets) == 0 {
-		if versionFilter != "" {
-			return nil, fmt.Errorf("no version %s found for %s", versionFilter, repo.Path)
-		}
-		return nil, fmt.Errorf("no versions found for %s", repo.Path)
-	}
-
-	// Sort versions
-	sort.Slice(sdkAssets, func(i, j int) bool {
-		return sdkAssets[i].Version > sdkAssets[j].Version
-	})
-
-	return sdkAssets, nil
 }
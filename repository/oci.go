@@ -0,0 +1,350 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strigo/config"
+	"strigo/downloader/network"
+	"strigo/logging"
+	"strings"
+)
+
+// OCI media types of manifests, in order of preference for the Accept header
+var ociManifestTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+}
+
+// ociTitleAnnotation holds the file name of an ORAS layer
+const ociTitleAnnotation = "org.opencontainers.image.title"
+
+// challengeParam matches the key="value" parameters of a WWW-Authenticate header
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// OCIClient implements RepositoryClient for SDK archives pushed as ORAS artifacts
+// to an OCI distribution registry (Harbor, Zot, distribution)
+type OCIClient struct{}
+
+// NewOCIClient creates a new OCIClient. Versions are the artifact tags,
+// so no version patterns are needed.
+func NewOCIClient() *OCIClient {
+	return &OCIClient{}
+}
+
+// ociDescriptor references a manifest or a blob
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Platform    *struct {
+		OS           string `json:"os"`
+		Architecture string `json:"architecture"`
+	} `json:"platform,omitempty"`
+}
+
+// ociManifest is an image manifest or, when Manifests is set, an image index
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+// ociSession sends registry requests, answering Bearer token challenges
+type ociSession struct {
+	client *network.Client
+	token  string // Bearer token, configured or obtained from the last challenge
+}
+
+// newOCISession creates a session with the credentials and network settings of registry
+func newOCISession(registry config.Registry) (*ociSession, error) {
+	settings, err := network.SettingsFromRegistry(registry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network settings: %w", err)
+	}
+	// A configured token is sent by the session so that a challenge can replace it
+	settings.Headers.Del("Authorization")
+	return &ociSession{
+		client: network.NewClientWithSettings(registry.Username, registry.Password, settings),
+		token:  registry.Token,
+	}, nil
+}
+
+// ociRepositoryName returns the OCI repository of repo: its repository and path joined by "/"
+func ociRepositoryName(repo config.SDKRepository) string {
+	var parts []string
+	for _, part := range []string{repo.Repository, repo.Path} {
+		if part = strings.Trim(part, "/"); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// GetAvailableVersions lists the tags of the repo's OCI repository with the distribution API
+// (/v2/<name>/tags/list). registry.APIURL is the registry base URL, e.g. https://harbor.example.com
+// The manifest of the selected tag is resolved by ResolveManifest before downloading.
+func (c *OCIClient) GetAvailableVersions(repo config.SDKRepository, registry config.Registry, versionFilter string) ([]SDKAsset, error) {
+	baseURL := strings.TrimSuffix(registry.APIURL, "/")
+	name := ociRepositoryName(repo)
+	if name == "" {
+		return nil, fmt.Errorf("repository is required for oci registries")
+	}
+
+	session, err := newOCISession(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	nextURL := fmt.Sprintf("%s/v2/%s/tags/list", baseURL, escapePath(name))
+	for nextURL != "" {
+		logging.LogDebug("🔍 OCI tags URL: %s", nextURL)
+		resp, err := session.do(nextURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query OCI registry: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("OCI registry returned %d: Check if the repository %s exists", resp.StatusCode, name)
+		}
+
+		var data struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&data)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode JSON response: %v", err)
+		}
+		tags = append(tags, data.Tags...)
+
+		nextURL, err = nextLink(nextURL, resp.Header.Get("Link"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	logging.LogDebug("📦 Received %d tags from %s", len(tags), name)
+
+	var sdkAssets []SDKAsset
+	for _, tag := range tags {
+		// Floating tags such as "latest" are not versions
+		if !strings.ContainsAny(tag, "0123456789") {
+			continue
+		}
+		sdkAssets = append(sdkAssets, SDKAsset{
+			Version:     tag,
+			Filename:    tag,
+			ManifestURL: fmt.Sprintf("%s/v2/%s/manifests/%s", baseURL, escapePath(name), url.PathEscape(tag)),
+		})
+	}
+
+	return finalizeAssets(sdkAssets, name, versionFilter)
+}
+
+// ResolveManifest fetches the OCI manifest of an asset and points it at its layer blob,
+// whose digest becomes the expected checksum. For image indexes, the manifest of the
+// asset's OS and architecture is used, the host platform's when they are not set. Assets without a manifest URL are left unchanged.
+func ResolveManifest(asset *SDKAsset, registry config.Registry) error {
+	if asset.ManifestURL == "" {
+		return nil
+	}
+
+	session, err := newOCISession(registry)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := session.manifest(asset.ManifestURL)
+	if err != nil {
+		return err
+	}
+	if len(manifest.Manifests) > 0 {
+		platform := HostPlatform()
+		if asset.OS != "" && asset.Arch != "" {
+			platform = Platform{OS: asset.OS, Arch: asset.Arch}
+		}
+		platformManifest, err := selectPlatformManifest(manifest.Manifests, platform)
+		if err != nil {
+			return err
+		}
+		manifest, err = session.manifest(resolveReference(asset.ManifestURL, platformManifest.Digest))
+		if err != nil {
+			return err
+		}
+	}
+
+	layer, err := selectLayer(manifest.Layers)
+	if err != nil {
+		return err
+	}
+	algorithm, digest, ok := strings.Cut(layer.Digest, ":")
+	if !ok {
+		return fmt.Errorf("invalid layer digest %q", layer.Digest)
+	}
+
+	asset.DownloadUrl = resolveReference(strings.Replace(asset.ManifestURL, "/manifests/", "/blobs/", 1), layer.Digest)
+	asset.Size = layer.Size
+	asset.Checksum = digest
+	asset.ChecksumAlgorithm = algorithm
+	asset.BearerToken = session.token
+	logging.LogDebug("📦 OCI layer %s (%d bytes): %s", layer.Annotations[ociTitleAnnotation], layer.Size, layer.Digest)
+	return nil
+}
+
+// manifest fetches and decodes a manifest or image index
+func (s *ociSession) manifest(manifestURL string) (*ociManifest, error) {
+	logging.LogDebug("🔍 OCI manifest URL: %s", manifestURL)
+	resp, err := s.do(manifestURL, http.Header{"Accept": {strings.Join(ociManifestTypes, ", ")}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OCI manifest: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OCI registry returned %d for manifest %s", resp.StatusCode, manifestURL)
+	}
+
+	var manifest ociManifest
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode OCI manifest: %v", err)
+	}
+	return &manifest, nil
+}
+
+// do performs a GET request. On a 401 with a Bearer challenge, it requests a token from the
+// challenge realm, using the registry credentials if any, and replays the request with it.
+func (s *ociSession) do(requestURL string, header http.Header) (*http.Response, error) {
+	send := func() (*http.Response, error) {
+		h := header.Clone()
+		if h == nil {
+			h = http.Header{}
+		}
+		if s.token != "" {
+			h.Set("Authorization", "Bearer "+s.token)
+		}
+		return s.client.Do("GET", requestURL, h)
+	}
+
+	resp, err := send()
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return nil, fmt.Errorf("authentication required by %s", requestURL)
+	}
+	if err := s.fetchToken(challenge); err != nil {
+		return nil, err
+	}
+	return send()
+}
+
+// fetchToken obtains a Bearer token from the realm of a WWW-Authenticate challenge
+func (s *ociSession) fetchToken(challenge string) error {
+	params := make(map[string]string)
+	for _, m := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(m[1])] = m[2]
+	}
+	if params["realm"] == "" {
+		return fmt.Errorf("invalid authentication challenge: %s", challenge)
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil {
+		return fmt.Errorf("invalid token realm %s: %w", params["realm"], err)
+	}
+	query := tokenURL.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	tokenURL.RawQuery = query.Encode()
+	logging.LogDebug("🔐 Requesting OCI token from %s", tokenURL)
+
+	// Basic Auth is added by the network client when credentials are configured
+	resp, err := s.client.Do("GET", tokenURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to request OCI token: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token endpoint returned %d: Check the registry credentials", resp.StatusCode)
+	}
+
+	var data struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return fmt.Errorf("failed to decode token response: %v", err)
+	}
+	s.token = data.Token
+	if s.token == "" {
+		s.token = data.AccessToken
+	}
+	if s.token == "" {
+		return fmt.Errorf("token endpoint returned no token")
+	}
+	return nil
+}
+
+// selectPlatformManifest picks the manifest of a platform from an image index
+func selectPlatformManifest(manifests []ociDescriptor, platform Platform) (ociDescriptor, error) {
+	for _, m := range manifests {
+		if m.Platform == nil || (m.Platform.OS == platform.OS && m.Platform.Architecture == platform.Arch) {
+			return m, nil
+		}
+	}
+	return ociDescriptor{}, fmt.Errorf("no manifest for %s in image index", platform)
+}
+
+// selectLayer picks the SDK archive of a manifest: its only layer, or the first one
+// carrying a file name annotation
+func selectLayer(layers []ociDescriptor) (ociDescriptor, error) {
+	if len(layers) == 1 {
+		return layers[0], nil
+	}
+	for _, layer := range layers {
+		if layer.Annotations[ociTitleAnnotation] != "" {
+			return layer, nil
+		}
+	}
+	return ociDescriptor{}, fmt.Errorf("cannot select the SDK archive among %d layers", len(layers))
+}
+
+// resolveReference replaces the last path segment of a manifest or blob URL with a digest
+func resolveReference(rawURL, digest string) string {
+	return rawURL[:strings.LastIndex(rawURL, "/")+1] + digest
+}
+
+// nextLink returns the absolute URL of the rel="next" link of a paginated response
+func nextLink(current, header string) (string, error) {
+	if header == "" || !strings.Contains(header, `rel="next"`) {
+		return "", nil
+	}
+	start, end := strings.Index(header, "<"), strings.Index(header, ">")
+	if start < 0 || end < start {
+		return "", fmt.Errorf("invalid Link header: %s", header)
+	}
+	base, err := url.Parse(current)
+	if err != nil {
+		return "", err
+	}
+	next, err := url.Parse(header[start+1 : end])
+	if err != nil {
+		return "", fmt.Errorf("invalid Link header: %s", header)
+	}
+	return base.ResolveReference(next).String(), nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"runtime"
+	"strings"
+)
+
+// Platform is the operating system and architecture SDKs are selected for,
+// named like GOOS and GOARCH ("linux", "amd64")
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+// HostPlatform returns the platform strigo runs on
+func HostPlatform() Platform {
+	return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+}
+
+// String returns the platform as "os/arch"
+func (p Platform) String() string {
+	return p.OS + "/" + p.Arch
+}
+
+// Matches reports whether an asset runs on the platform. Assets whose OS or
+// architecture is unknown match any platform.
+func (p Platform) Matches(asset SDKAsset) bool {
+	if asset.OS != "" && p.OS != "" && asset.OS != p.OS {
+		return false
+	}
+	if asset.Arch != "" && p.Arch != "" && asset.Arch != p.Arch {
+		return false
+	}
+	return true
+}
+
+// NormalizeOS maps the operating system names used by vendors to GOOS names:
+// "mac", "macos", "osx" → "darwin", "win" → "windows", "alpine-linux" → "linux"
+func NormalizeOS(name string) string {
+	switch name = strings.ToLower(name); name {
+	case "mac", "macos", "macosx", "osx":
+		return "darwin"
+	case "win", "windows":
+		return "windows"
+	case "alpine", "alpine-linux", "linux-musl", "linux_musl":
+		return "linux"
+	default:
+		return name
+	}
+}
+
+// IsMuslOS reports whether a vendor operating system name denotes a musl libc build
+// ("alpine-linux", "linux_musl"), which NormalizeOS reports as plain "linux"
+func IsMuslOS(name string) bool {
+	switch strings.ToLower(name) {
+	case "alpine", "alpine-linux", "linux-musl", "linux_musl":
+		return true
+	default:
+		return false
+	}
+}
+
+// NormalizeArch maps the architecture names used by vendors to GOARCH names:
+// "x64", "x86_64" → "amd64", "aarch64" → "arm64", "x86", "i686" → "386"
+func NormalizeArch(name string) string {
+	switch name = strings.ToLower(name); name {
+	case "x64", "x86_64", "x86-64", "amd64":
+		return "amd64"
+	case "aarch64", "arm64":
+		return "arm64"
+	case "x86", "x86-32", "x32", "i386", "i586", "i686", "386":
+		return "386"
+	case "arm32", "armv6", "armv6l", "armv7", "armv7l", "armhf", "armel":
+		return "arm"
+	case "ppc64el":
+		return "ppc64le"
+	default:
+		return name
+	}
+}
+
+// filterPlatform keeps the assets running on the platform, one per version and variant. A file
+// built for the platform is preferred to a platform-independent one of the same version.
+func filterPlatform(assets []SDKAsset, platform Platform) []SDKAsset {
+	var filtered []SDKAsset
+	index := make(map[string]int) // Asset name → position in filtered
+	for _, asset := range assets {
+		if !platform.Matches(asset) {
+			continue
+		}
+		if i, seen := index[asset.Name()]; seen {
+			if filtered[i].OS == "" && filtered[i].Arch == "" {
+				filtered[i] = asset
+			}
+			continue
+		}
+		index[asset.Name()] = len(filtered)
+		filtered = append(filtered, asset)
+	}
+	return filtered
+}
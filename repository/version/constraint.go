@@ -0,0 +1,179 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Aliases accepted in place of a version
+const (
+	AliasLatest = "latest" // Newest release
+	AliasLTS    = "lts"    // Newest release of a long-term support line
+)
+
+// bound is a single comparison of a range constraint, like ">=17"
+type bound struct {
+	op      string
+	version Version
+}
+
+// Constraint selects versions among candidates. Supported forms:
+//   - "latest" and "lts"
+//   - a release prefix: "17", "17.0", "17.0.13" (any build of 17.0.13)
+//   - a wildcard: "17.0.x", "17.*"
+//   - a range of space or comma separated comparisons: ">=17 <21"
+//   - an exact version: "17.0.13_11"
+//
+// Only exact constraints match pre-releases.
+type Constraint struct {
+	raw    string
+	LTS    bool     // Only long-term support lines match, decided by the caller
+	exact  *Version // Exact version, nil otherwise
+	prefix []int    // Leading release components that must match
+	bounds []bound  // Comparisons that must all hold
+}
+
+// rangeOperators are the comparison operators of range constraints, longest first
+var rangeOperators = []string{">=", "<=", ">", "<", "="}
+
+// ParseConstraint parses a version constraint or alias
+func ParseConstraint(s string) (Constraint, error) {
+	s = strings.TrimSpace(s)
+	c := Constraint{raw: s}
+
+	switch strings.ToLower(s) {
+	case "":
+		return Constraint{}, fmt.Errorf("empty version constraint")
+	case AliasLatest:
+		return c, nil
+	case AliasLTS:
+		c.LTS = true
+		return c, nil
+	}
+
+	if strings.ContainsAny(s, "<>=") {
+		for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' }) {
+			op := ""
+			for _, candidate := range rangeOperators {
+				if strings.HasPrefix(field, candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return Constraint{}, fmt.Errorf("invalid version constraint %q: %q has no comparison operator", s, field)
+			}
+			v, err := Parse(strings.TrimPrefix(field, op))
+			if err != nil {
+				return Constraint{}, fmt.Errorf("invalid version constraint %q: %w", s, err)
+			}
+			c.bounds = append(c.bounds, bound{op: op, version: v})
+		}
+		return c, nil
+	}
+
+	// Wildcards: 17.0.x, 17.*
+	if trimmed := strings.TrimRight(strings.TrimSuffix(strings.TrimSuffix(s, "x"), "*"), "."); trimmed != s {
+		prefix, err := parseNumbers(trimmed)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("invalid version constraint %q", s)
+		}
+		c.prefix = prefix
+		return c, nil
+	}
+
+	v, err := Parse(s)
+	if err != nil {
+		return Constraint{}, fmt.Errorf("invalid version constraint %q: %w", s, err)
+	}
+	// "17", "17.0" and "17.0.13" select a release line, the newest build of it: only a version
+	// with a build, pre-release or qualifier is exact
+	if v.PreRelease == "" && len(v.Build) == 0 && v.Qualifier == "" {
+		c.prefix = v.Release
+		return c, nil
+	}
+	c.exact = &v
+	return c, nil
+}
+
+// String returns the constraint as it was parsed
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// IsExact reports whether the constraint names a single version
+func (c Constraint) IsExact() bool {
+	return c.exact != nil
+}
+
+// Major returns the major version the constraint is restricted to, 0 if it spans several
+func (c Constraint) Major() int {
+	switch {
+	case c.exact != nil:
+		return c.exact.Major()
+	case len(c.prefix) > 0:
+		return c.prefix[0]
+	}
+	return 0
+}
+
+// Match reports whether v satisfies the constraint, ignoring the LTS restriction
+func (c Constraint) Match(v Version) bool {
+	if c.exact != nil {
+		return v.Compare(*c.exact) == 0
+	}
+	if v.IsPreRelease() {
+		return false
+	}
+
+	for i, n := range c.prefix {
+		if i >= len(v.Release) {
+			if n != 0 {
+				return false
+			}
+		} else if v.Release[i] != n {
+			return false
+		}
+	}
+
+	for _, b := range c.bounds {
+		cmp := compareNumbers(v.Release, b.version.Release)
+		if len(b.version.Build) > 0 || b.version.PreRelease != "" {
+			cmp = v.Compare(b.version)
+		}
+		var ok bool
+		switch b.op {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		case "=":
+			ok = cmp == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Select returns the newest candidate matching the constraint and accepted by accept (nil to
+// accept all), or "" when none does. Candidates that cannot be parsed are ignored.
+func (c Constraint) Select(candidates []string, accept func(Version) bool) string {
+	var best Version
+	found := false
+	for _, candidate := range candidates {
+		v, err := Parse(candidate)
+		if err != nil || !c.Match(v) || (accept != nil && !accept(v)) {
+			continue
+		}
+		if !found || best.Less(v) {
+			best, found = v, true
+		}
+	}
+	return best.String()
+}
@@ -65,14 +65,26 @@ func ExtractMajor(version string) string {
 
 // CompareVersions compares two versions and returns true if v1 is older than v2.
 //
-// This function normalizes version strings by converting separators (u, _) to dots,
-// then compares each numeric part sequentially.
+// Both versions are parsed with Parse and compared with Version.Compare. Versions that do not
+// follow the version grammar fall back to a loose comparison of their numeric parts.
 //
 // Examples:
 //   - CompareVersions("11.0.26_4", "11.0.27_5") → true (11.0.26 < 11.0.27)
 //   - CompareVersions("8u442b06", "8u432b06") → false (442 > 432)
-//   - CompareVersions("21.0.6", "21.0.6_7") → true (6 < 6.7)
+//   - CompareVersions("21.0.6", "21.0.6_7") → true (no build < build 7)
+//   - CompareVersions("21.0.8-beta.2", "21.0.8") → true (pre-release < release)
 func CompareVersions(v1, v2 string) bool {
+	p1, err1 := Parse(v1)
+	p2, err2 := Parse(v2)
+	if err1 == nil && err2 == nil {
+		return p1.Less(p2)
+	}
+	return compareLoose(v1, v2)
+}
+
+// compareLoose returns true if v1 is older than v2, comparing the numeric parts
+// of versions that Parse rejects
+func compareLoose(v1, v2 string) bool {
 	// Normalize versions to handle different formats
 	// Convert: "8u442b06" → "8.442.06"
 	//          "11.0.26_4" → "11.0.26.4"
@@ -5,6 +5,7 @@ import (
 	"os"
 	"regexp"
 	"strigo/logging"
+	"strings"
 
 	"github.com/pelletier/go-toml"
 )
@@ -15,6 +16,59 @@ type Pattern struct {
 	Type        string   `toml:"type"`
 	Description string   `toml:"description"`
 	Patterns    []string `toml:"patterns"`
+
+	// Platform of the files matched by the patterns, when they do not capture it
+	// with (?P<os>...) and (?P<arch>...) groups
+	OS   string `toml:"os,omitempty"`
+	Arch string `toml:"arch,omitempty"`
+}
+
+// Match is the result of matching a path against a pattern
+type Match struct {
+	Version string // The "version" group, or else the first unnamed group
+	Build   string // The "build" group: vendor build number, such as Zulu's "21.38.21"
+	Variant string // The "variant" group: flavour of the build, such as "crac", "fx" or "musl"
+	OS      string // The "os" group, or else the pattern's os field, as found in the path
+	Arch    string // The "arch" group, or else the pattern's arch field, as found in the path
+	Pattern string // Name of the matching pattern
+}
+
+// match matches a path against one regex of a pattern, returning nil if it does not match.
+// Group names may be repeated in alternatives; the group that took part in the match is used.
+func (pattern Pattern) match(re *regexp.Regexp, path string) *Match {
+	matches := re.FindStringSubmatch(path)
+	if matches == nil {
+		return nil
+	}
+
+	m := &Match{OS: pattern.OS, Arch: pattern.Arch, Pattern: pattern.Name}
+	for i, name := range re.SubexpNames() {
+		if i == 0 || matches[i] == "" {
+			continue
+		}
+		switch name {
+		case "":
+			if m.Version == "" {
+				m.Version = matches[i]
+			}
+		case "build":
+			m.Build = matches[i]
+		case "variant":
+			m.Variant = strings.ToLower(matches[i])
+		case "os":
+			m.OS = matches[i]
+		case "arch":
+			m.Arch = matches[i]
+		}
+	}
+	// A named version group takes precedence over unnamed ones
+	if i := re.SubexpIndex("version"); i > 0 && matches[i] != "" {
+		m.Version = matches[i]
+	}
+	if m.Version == "" {
+		return nil
+	}
+	return m
 }
 
 // PatternConfig holds all pattern configurations
@@ -82,10 +136,9 @@ func (p *Parser) ExtractVersion(path string) (version string, patternName string
 				continue
 			}
 
-			if matches := re.FindStringSubmatch(path); len(matches) > 1 {
-				version := matches[1]
-				logging.LogDebug("✅ Matched pattern '%s' (%s): extracted version %s", pattern.Name, pattern.Description, version)
-				return version, pattern.Name, nil
+			if m := pattern.match(re, path); m != nil {
+				logging.LogDebug("✅ Matched pattern '%s' (%s): extracted version %s", pattern.Name, pattern.Description, m.Version)
+				return m.Version, pattern.Name, nil
 			}
 		}
 	}
@@ -95,6 +148,15 @@ func (p *Parser) ExtractVersion(path string) (version string, patternName string
 
 // ExtractVersionByType extracts a version using only patterns for a specific SDK type
 func (p *Parser) ExtractVersionByType(path string, sdkType string) (version string, patternName string, err error) {
+	m, err := p.MatchByType(path, sdkType)
+	if err != nil {
+		return "", "", err
+	}
+	return m.Version, m.Pattern, nil
+}
+
+// MatchByType matches a path using only patterns for a specific SDK type
+func (p *Parser) MatchByType(path string, sdkType string) (*Match, error) {
 	logging.LogDebug("🔍 Extracting version from path (type filter: %s): %s", sdkType, path)
 
 	for _, pattern := range p.patterns {
@@ -110,15 +172,14 @@ func (p *Parser) ExtractVersionByType(path string, sdkType string) (version stri
 				continue
 			}
 
-			if matches := re.FindStringSubmatch(path); len(matches) > 1 {
-				version := matches[1]
-				logging.LogDebug("✅ Matched pattern '%s' (%s): extracted version %s", pattern.Name, pattern.Description, version)
-				return version, pattern.Name, nil
+			if m := pattern.match(re, path); m != nil {
+				logging.LogDebug("✅ Matched pattern '%s' (%s): extracted version %s", pattern.Name, pattern.Description, m.Version)
+				return m, nil
 			}
 		}
 	}
 
-	return "", "", fmt.Errorf("no pattern matched for path: %s (type: %s)", path, sdkType)
+	return nil, fmt.Errorf("no pattern matched for path: %s (type: %s)", path, sdkType)
 }
 
 // ExtractVersionByDistribution extracts a version using only patterns for a specific distribution
@@ -138,10 +199,9 @@ func (p *Parser) ExtractVersionByDistribution(path string, distribution string)
 				continue
 			}
 
-			if matches := re.FindStringSubmatch(path); len(matches) > 1 {
-				version := matches[1]
-				logging.LogDebug("✅ Matched pattern '%s' (%s): extracted version %s", pattern.Name, pattern.Description, version)
-				return version, pattern.Name, nil
+			if m := pattern.match(re, path); m != nil {
+				logging.LogDebug("✅ Matched pattern '%s' (%s): extracted version %s", pattern.Name, pattern.Description, m.Version)
+				return m.Version, pattern.Name, nil
 			}
 		}
 	}
@@ -0,0 +1,193 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionGrammar matches the version formats published by SDK vendors:
+// [prefix] release [u update] [-pre] [build] [-qualifier], where
+//   - release is dot-separated numbers ("17.0.11", Corretto "11.0.26.4.1")
+//   - update is the legacy Java 8 update number ("8u442")
+//   - pre is pre-release identifiers ("-ea", "-beta.2", "-rc1")
+//   - build is a JEP 223 "+7.1", Temurin "_6" or legacy "b06" / "-b06" build number
+//   - qualifier is any other trailing text ("-LTS", ".r25")
+var versionGrammar = regexp.MustCompile(`(?i)^(?:[a-z]+-?)?(\d+(?:\.\d+)*)(?:u(\d+))?(?:-((?:ea|alpha|beta|rc|pre|preview|snapshot)[0-9a-z.]*))?(?:(?:\+|_|-?b)(\d+(?:\.\d+)*))?(?:[-.](.+))?$`)
+
+// preReleaseTag matches qualifiers denoting a pre-release ("24+20-ea")
+var preReleaseTag = regexp.MustCompile(`(?i)^(?:ea|alpha|beta|rc|pre|preview|snapshot)`)
+
+// Version is a parsed SDK version
+type Version struct {
+	Release    []int  // Numeric components: "17.0.11" → [17 0 11], "8u442" → [8 0 442]
+	PreRelease string // Pre-release identifiers ("ea", "beta.2"), empty for releases
+	Build      []int  // Build number: "+7.1" → [7 1], "_6" → [6], "b06" → [6]
+	Qualifier  string // Other trailing text, only used to break ties
+	raw        string
+}
+
+// Parse parses a version string
+//
+// Examples:
+//   - "17.0.11+7.1" → release 17.0.11, build 7.1
+//   - "8u442b06" → release 8.0.442, build 6
+//   - "21.0.5_11" → release 21.0.5, build 11
+//   - "11.0.26.4.1" → release 11.0.26.4.1
+//   - "21.0.8-beta.2" → release 21.0.8, pre-release beta.2
+func Parse(s string) (Version, error) {
+	m := versionGrammar.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+
+	v := Version{raw: s, PreRelease: strings.ToLower(m[3]), Qualifier: m[5]}
+	release, err := parseNumbers(m[1])
+	if err != nil {
+		return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+	}
+	if m[2] != "" {
+		// 8u442 is update 442 of Java 8, i.e. 8.0.442
+		update, err := strconv.Atoi(m[2])
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		release = append(release[:1], 0, update)
+	}
+	v.Release = release
+
+	if m[4] != "" {
+		if v.Build, err = parseNumbers(m[4]); err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+	}
+	if v.PreRelease == "" && preReleaseTag.MatchString(v.Qualifier) {
+		v.PreRelease, v.Qualifier = strings.ToLower(v.Qualifier), ""
+	}
+	return v, nil
+}
+
+// parseNumbers parses dot-separated numbers
+func parseNumbers(s string) ([]int, error) {
+	parts := strings.Split(s, ".")
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, err
+		}
+		numbers[i] = n
+	}
+	return numbers, nil
+}
+
+// String returns the version as it was parsed
+func (v Version) String() string {
+	return v.raw
+}
+
+// Major returns the first release component
+func (v Version) Major() int {
+	if len(v.Release) == 0 {
+		return 0
+	}
+	return v.Release[0]
+}
+
+// IsPreRelease reports whether the version is an early access or pre-release build
+func (v Version) IsPreRelease() bool {
+	return v.PreRelease != ""
+}
+
+// Compare returns -1, 0 or 1 if v is older than, equal to or newer than o.
+// Release components are compared numerically (missing ones count as 0), a pre-release is
+// older than the release, then build numbers are compared.
+func (v Version) Compare(o Version) int {
+	if c := compareNumbers(v.Release, o.Release); c != 0 {
+		return c
+	}
+	if c := comparePreRelease(v.PreRelease, o.PreRelease); c != 0 {
+		return c
+	}
+	if c := compareNumbers(v.Build, o.Build); c != 0 {
+		return c
+	}
+	return strings.Compare(v.Qualifier, o.Qualifier)
+}
+
+// Less reports whether v is older than o
+func (v Version) Less(o Version) bool {
+	return v.Compare(o) < 0
+}
+
+// compareNumbers compares two lists of numbers component by component
+func compareNumbers(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// comparePreRelease orders pre-releases before releases, and pre-release identifiers
+// like semantic versioning: numeric identifiers numerically, others lexically
+func comparePreRelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	aParts, bParts := splitIdentifiers(a), splitIdentifiers(b)
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		x, errX := strconv.Atoi(aParts[i])
+		y, errY := strconv.Atoi(bParts[i])
+		switch {
+		case errX == nil && errY == nil:
+			if x != y {
+				if x < y {
+					return -1
+				}
+				return 1
+			}
+		case errX == nil:
+			return -1
+		case errY == nil:
+			return 1
+		default:
+			if c := strings.Compare(aParts[i], bParts[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareNumbers([]int{len(aParts)}, []int{len(bParts)})
+}
+
+// splitIdentifiers splits a pre-release into identifiers: "beta.2" → [beta 2], "rc1" → [rc 1]
+func splitIdentifiers(s string) []string {
+	var parts []string
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == '.' || r == '-' }) {
+		i := strings.IndexAny(field, "0123456789")
+		if i > 0 {
+			parts = append(parts, field[:i], field[i:])
+		} else {
+			parts = append(parts, field)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,174 @@
+package unit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"strigo/config"
+	"strigo/repository"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// adoptiumBinary builds a feature_releases binary entry for a platform
+func adoptiumBinary(host, osName, arch, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"os":           osName,
+		"architecture": arch,
+		"image_type":   "jdk",
+		"package": map[string]interface{}{
+			"name":     name,
+			"link":     host + "/download/" + arch + "/" + name,
+			"checksum": "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+			"size":     190000000,
+		},
+	}
+}
+
+// mockAdoptium serves an Adoptium API with features 8 and 21 (LTS) and 22, each release
+// published for linux/mac on x64 and aarch64
+func mockAdoptium(t *testing.T, requests *[]string) *httptest.Server {
+	t.Helper()
+
+	releases := map[string][]string{
+		"8":  {"jdk8u432-b06"},
+		"21": {"jdk-21.0.5+11", "jdk-21.0.4+7"},
+		"22": {"jdk-22.0.2+9"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.Path)
+		host := "http://" + r.Host
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/v3/info/available_releases" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"available_releases":     []int{8, 21, 22},
+				"available_lts_releases": []int{8, 21},
+			})
+			return
+		}
+
+		feature := strings.TrimPrefix(r.URL.Path, "/v3/assets/feature_releases/")
+		feature = strings.TrimSuffix(feature, "/ga")
+		names, ok := releases[feature]
+		if !ok || r.URL.Query().Get("page") != "0" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "jdk", r.URL.Query().Get("image_type"))
+
+		var data []map[string]interface{}
+		for _, name := range names {
+			var binaries []map[string]interface{}
+			for _, osName := range []string{"linux", "mac"} {
+				for _, arch := range []string{"x64", "aarch64"} {
+					binaries = append(binaries, adoptiumBinary(host, osName, arch, osName+"-"+arch+"-"+name+".tar.gz"))
+				}
+			}
+			data = append(data, map[string]interface{}{
+				"release_name": name,
+				"release_type": "ga",
+				"binaries":     binaries,
+			})
+		}
+		_ = json.NewEncoder(w).Encode(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func adoptiumRepo() config.SDKRepository {
+	return config.SDKRepository{Type: "jdk", Registry: "adoptium"}
+}
+
+func TestAdoptiumClientWithMockServer(t *testing.T) {
+	var requests []string
+	server := mockAdoptium(t, &requests)
+	registry := config.Registry{Type: "adoptium", APIURL: server.URL + "/"}
+
+	assets, err := repository.FetchAvailableVersions(adoptiumRepo(), registry, "", true)
+	if runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" {
+		assert.Error(t, err)
+		return
+	}
+	require.NoError(t, err)
+	require.Len(t, assets, 4)
+
+	byVersion := make(map[string]repository.SDKAsset)
+	for _, asset := range assets {
+		byVersion[asset.Version] = asset
+	}
+	assert.Contains(t, byVersion, "8u432b06")
+	assert.Contains(t, byVersion, "21.0.4_7")
+
+	jdk21 := byVersion["21.0.5_11"]
+	assert.True(t, jdk21.LTS)
+	assert.Equal(t, "ga", jdk21.ReleaseType)
+	assert.Equal(t, "jdk", jdk21.ImageType)
+	assert.Equal(t, int64(190000000), jdk21.Size)
+	assert.Equal(t, "sha256", jdk21.ChecksumAlgorithm)
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", jdk21.Checksum)
+
+	// Only the binary of the host platform is kept, its architecture named like GOARCH
+	adoptiumArch := map[string]string{"amd64": "x64", "arm64": "aarch64"}[runtime.GOARCH]
+	assert.Equal(t, runtime.GOARCH, jdk21.Arch)
+	assert.Contains(t, jdk21.DownloadUrl, "/download/"+adoptiumArch+"/")
+
+	assert.False(t, byVersion["22.0.2_9"].LTS)
+}
+
+func TestAdoptiumClientQueriesOnlyFilteredFeature(t *testing.T) {
+	var requests []string
+	server := mockAdoptium(t, &requests)
+	registry := config.Registry{Type: "adoptium", APIURL: server.URL}
+
+	_, _ = repository.FetchAvailableVersions(adoptiumRepo(), registry, "21.0.5_11", true)
+	assert.Equal(t, []string{"/v3/info/available_releases", "/v3/assets/feature_releases/21/ga"}, requests)
+}
+
+func TestAdoptiumClientPagesThroughReleases(t *testing.T) {
+	// 45 releases of feature 17, served 20 per page at most
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v3/info/available_releases" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"available_releases": []int{17}, "available_lts_releases": []int{17}})
+			return
+		}
+
+		query := r.URL.Query()
+		pages = append(pages, query.Get("page"))
+		page, _ := strconv.Atoi(query.Get("page"))
+		pageSize, _ := strconv.Atoi(query.Get("page_size"))
+		assert.LessOrEqual(t, pageSize, 20)
+
+		var data []map[string]interface{}
+		for i := page * pageSize; i < (page+1)*pageSize && i < 45; i++ {
+			name := fmt.Sprintf("jdk-17.0.%d+1", 45-i)
+			data = append(data, map[string]interface{}{
+				"release_name": name,
+				"release_type": "ga",
+				"binaries":     []map[string]interface{}{adoptiumBinary("http://"+r.Host, "linux", "x64", name+".tar.gz")},
+			})
+		}
+		if len(data) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(data)
+	}))
+	defer server.Close()
+
+	registry := config.Registry{Type: "adoptium", APIURL: server.URL}
+	assets, err := repository.FetchAvailableVersions(adoptiumRepo(), registry, "", true, "", repository.Platform{OS: "linux", Arch: "amd64"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0", "1", "2"}, pages)
+	assert.Len(t, assets, 45)
+}
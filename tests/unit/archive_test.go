@@ -0,0 +1,502 @@
+package unit
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strigo/config"
+	"strigo/downloader"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTarGz writes a tar.gz archive containing the given entries, in order, and returns its path
+func writeTarGz(t *testing.T, entries []tar.Header, contents map[string]string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	_, err := gzw.Write(buildTar(t, entries, contents))
+	require.NoError(t, err)
+	require.NoError(t, gzw.Close())
+
+	path := filepath.Join(t.TempDir(), "archive.tar.gz")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+	return path
+}
+
+// buildTar returns an uncompressed tar stream containing the given entries, in order
+func buildTar(t *testing.T, entries []tar.Header, contents map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	for _, header := range entries {
+		hdr := header
+		content := contents[hdr.Name]
+		if hdr.Typeflag == tar.TypeReg {
+			hdr.Size = int64(len(content))
+		}
+		require.NoError(t, tw.WriteHeader(&hdr))
+		if hdr.Typeflag == tar.TypeReg {
+			_, err := tw.Write([]byte(content))
+			require.NoError(t, err)
+		}
+	}
+
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func TestExtractTarPreservesLinksAndTimes(t *testing.T) {
+	modTime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	archive := writeTarGz(t, []tar.Header{
+		{Name: "./", Typeflag: tar.TypeDir, Mode: 0755, ModTime: modTime},
+		{Name: "node/", Typeflag: tar.TypeDir, Mode: 0755, ModTime: modTime},
+		{Name: "node/lib/", Typeflag: tar.TypeDir, Mode: 0755, ModTime: modTime},
+		{Name: "node/lib/npm-cli.js", Typeflag: tar.TypeReg, Mode: 0755, ModTime: modTime},
+		{Name: "node/bin/npm", Typeflag: tar.TypeSymlink, Linkname: "../lib/npm-cli.js", ModTime: modTime},
+		{Name: "node/LICENSE", Typeflag: tar.TypeReg, Mode: 0644, ModTime: modTime},
+		{Name: "node/legal/LICENSE", Typeflag: tar.TypeLink, Linkname: "node/LICENSE", ModTime: modTime},
+	}, map[string]string{
+		"node/lib/npm-cli.js": "#!/usr/bin/env node",
+		"node/LICENSE":        "MIT",
+	})
+
+	dest := t.TempDir()
+	require.NoError(t, downloader.NewExtractor().Extract(archive, dest))
+
+	link := filepath.Join(dest, "node", "bin", "npm")
+	target, err := os.Readlink(link)
+	require.NoError(t, err)
+	assert.Equal(t, "../lib/npm-cli.js", target)
+	data, err := os.ReadFile(link)
+	require.NoError(t, err)
+	assert.Equal(t, "#!/usr/bin/env node", string(data))
+
+	original, err := os.Stat(filepath.Join(dest, "node", "LICENSE"))
+	require.NoError(t, err)
+	hardlink, err := os.Stat(filepath.Join(dest, "node", "legal", "LICENSE"))
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(original, hardlink), "hardlink should share the original inode")
+
+	for _, path := range []string{"node", "node/lib", "node/lib/npm-cli.js", "node/LICENSE"} {
+		info, err := os.Lstat(filepath.Join(dest, path))
+		require.NoError(t, err)
+		assert.True(t, info.ModTime().Equal(modTime), "mtime of %s: %v", path, info.ModTime())
+	}
+	linkInfo, err := os.Lstat(link)
+	require.NoError(t, err)
+	assert.True(t, linkInfo.ModTime().Equal(modTime), "symlink mtime: %v", linkInfo.ModTime())
+}
+
+func TestExtractTarStripsSetuid(t *testing.T) {
+	archive := writeTarGz(t, []tar.Header{
+		{Name: "bin/tool", Typeflag: tar.TypeReg, Mode: 0o4755 | 0o2000},
+	}, map[string]string{"bin/tool": "binary"})
+
+	dest := t.TempDir()
+	require.NoError(t, downloader.NewExtractor().Extract(archive, dest))
+
+	info, err := os.Stat(filepath.Join(dest, "bin", "tool"))
+	require.NoError(t, err)
+	assert.Zero(t, info.Mode()&(os.ModeSetuid|os.ModeSetgid), "setuid/setgid must be stripped")
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
+
+func TestExtractTarRejectsEscapingLinks(t *testing.T) {
+	outside := t.TempDir()
+
+	tests := []struct {
+		name    string
+		entries []tar.Header
+	}{
+		{
+			name: "absolute symlink",
+			entries: []tar.Header{
+				{Name: "evil", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"},
+			},
+		},
+		{
+			name: "relative symlink escaping",
+			entries: []tar.Header{
+				{Name: "dir/evil", Typeflag: tar.TypeSymlink, Linkname: "../../outside"},
+			},
+		},
+		{
+			name: "write through chained symlinks",
+			entries: []tar.Header{
+				{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."},
+				{Name: "a/b", Typeflag: tar.TypeSymlink, Linkname: ".."},
+				{Name: "a/b/escaped", Typeflag: tar.TypeReg, Mode: 0644},
+			},
+		},
+		{
+			name: "symlink through an extracted symlink",
+			entries: []tar.Header{
+				{Name: "d/", Typeflag: tar.TypeDir, Mode: 0755},
+				{Name: "d/up", Typeflag: tar.TypeSymlink, Linkname: ".."},
+				{Name: "evil", Typeflag: tar.TypeSymlink, Linkname: "d/up/../escaped"},
+			},
+		},
+		{
+			name: "symlink through a symlink extracted later",
+			entries: []tar.Header{
+				{Name: "evil", Typeflag: tar.TypeSymlink, Linkname: "later/../escaped"},
+				{Name: "later", Typeflag: tar.TypeSymlink, Linkname: "."},
+			},
+		},
+		{
+			name: "hardlink outside",
+			entries: []tar.Header{
+				{Name: "evil", Typeflag: tar.TypeLink, Linkname: "../" + filepath.Base(outside) + "/file"},
+			},
+		},
+		{
+			name: "path traversal",
+			entries: []tar.Header{
+				{Name: "../escaped", Typeflag: tar.TypeReg, Mode: 0644},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := writeTarGz(t, tt.entries, nil)
+			parent := t.TempDir()
+			dest := filepath.Join(parent, "dest")
+
+			err := downloader.NewExtractor().Extract(archive, dest)
+			assert.Error(t, err)
+			assert.NoFileExists(t, filepath.Join(parent, "escaped"))
+		})
+	}
+}
+
+// zipEntry describes an entry of a test zip archive
+type zipEntry struct {
+	name    string
+	mode    os.FileMode
+	content string
+}
+
+// writeZip writes a zip archive containing the given entries, in order, and returns its path
+func writeZip(t *testing.T, entries []zipEntry) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate}
+		header.SetMode(entry.mode)
+		w, err := zw.CreateHeader(header)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	path := filepath.Join(t.TempDir(), "archive.zip")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+	return path
+}
+
+func TestExtractZip(t *testing.T) {
+	archive := writeZip(t, []zipEntry{
+		{name: "gradle-8.5/", mode: os.ModeDir | 0755},
+		{name: "gradle-8.5/bin/gradle", mode: 0755, content: "#!/bin/sh"},
+		{name: "gradle-8.5/lib/gradle.jar", mode: 0644, content: "jar"},
+		{name: "gradle-8.5/bin/gradlew", mode: os.ModeSymlink | 0777, content: "gradle"},
+		{name: "gradle-8.5/bin/suid", mode: os.ModeSetuid | 0755, content: "x"},
+	})
+
+	dest := t.TempDir()
+	require.NoError(t, downloader.NewExtractor().Extract(archive, dest))
+
+	info, err := os.Stat(filepath.Join(dest, "gradle-8.5", "bin", "gradle"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	info, err = os.Stat(filepath.Join(dest, "gradle-8.5", "lib", "gradle.jar"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	target, err := os.Readlink(filepath.Join(dest, "gradle-8.5", "bin", "gradlew"))
+	require.NoError(t, err)
+	assert.Equal(t, "gradle", target)
+
+	info, err = os.Stat(filepath.Join(dest, "gradle-8.5", "bin", "suid"))
+	require.NoError(t, err)
+	assert.Zero(t, info.Mode()&os.ModeSetuid, "setuid must be stripped")
+}
+
+func TestExtractZipRejectsEscapingEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []zipEntry
+	}{
+		{
+			name:    "zip slip",
+			entries: []zipEntry{{name: "../escaped", mode: 0644, content: "x"}},
+		},
+		{
+			name: "symlink escaping",
+			entries: []zipEntry{
+				{name: "link", mode: os.ModeSymlink | 0777, content: ".."},
+				{name: "link/escaped", mode: 0644, content: "x"},
+			},
+		},
+		{
+			name:    "absolute symlink",
+			entries: []zipEntry{{name: "link", mode: os.ModeSymlink | 0777, content: "/etc"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := writeZip(t, tt.entries)
+			parent := t.TempDir()
+
+			err := downloader.NewExtractor().Extract(archive, filepath.Join(parent, "dest"))
+			assert.Error(t, err)
+			assert.NoFileExists(t, filepath.Join(parent, "escaped"))
+		})
+	}
+}
+
+// compressWith pipes data through a compression command, skipping the test when it is not installed
+func compressWith(t *testing.T, data []byte, name string, args ...string) []byte {
+	t.Helper()
+
+	path, err := exec.LookPath(name)
+	if err != nil {
+		t.Skipf("%s not installed", name)
+	}
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	return out
+}
+
+// compressZstd compresses data in the zstd format
+func compressZstd(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	defer encoder.Close()
+	return encoder.EncodeAll(data, nil)
+}
+
+func TestExtractDetectsFormatByMagicBytes(t *testing.T) {
+	entries := []tar.Header{{Name: "sdk/bin/tool", Typeflag: tar.TypeReg, Mode: 0755}}
+	contents := map[string]string{"sdk/bin/tool": "tool"}
+	plain := buildTar(t, entries, contents)
+	gzipped, err := os.ReadFile(writeTarGz(t, entries, contents))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		fileName string
+		data     func(t *testing.T) []byte
+		format   downloader.ArchiveFormat
+	}{
+		{"tgz", "sdk.tgz", func(t *testing.T) []byte { return gzipped }, downloader.FormatTarGz},
+		{"gzip without extension", "sdk.bin", func(t *testing.T) []byte { return gzipped }, downloader.FormatTarGz},
+		{"plain tar", "sdk.tar", func(t *testing.T) []byte { return plain }, downloader.FormatTar},
+		{"bzip2", "sdk.tar.bz2", func(t *testing.T) []byte { return compressWith(t, plain, "bzip2", "-c") }, downloader.FormatTarBz2},
+		{"zstd", "sdk.tar.zst", func(t *testing.T) []byte { return compressZstd(t, plain) }, downloader.FormatTarZst},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), tt.fileName)
+			require.NoError(t, os.WriteFile(archive, tt.data(t), 0644))
+
+			format, err := downloader.DetectFormat(archive)
+			require.NoError(t, err)
+			assert.Equal(t, tt.format, format)
+
+			dest := t.TempDir()
+			require.NoError(t, downloader.NewExtractor().Extract(archive, dest))
+			data, err := os.ReadFile(filepath.Join(dest, "sdk", "bin", "tool"))
+			require.NoError(t, err)
+			assert.Equal(t, "tool", string(data))
+		})
+	}
+}
+
+func TestExtractRejectsUnknownFormat(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "sdk.tar.gz")
+	require.NoError(t, os.WriteFile(archive, []byte("not an archive"), 0644))
+
+	err := downloader.NewExtractor().Extract(archive, t.TempDir())
+	assert.ErrorContains(t, err, "unsupported archive format")
+}
+
+func TestExtractCorruptZstdFails(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "sdk.tar.zst")
+	require.NoError(t, os.WriteFile(archive, []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 0x01, 0x02}, 0644))
+
+	assert.Error(t, downloader.NewExtractor().Extract(archive, t.TempDir()))
+}
+
+func TestExtractStripComponents(t *testing.T) {
+	jdkEntries := []tar.Header{
+		{Name: "./jdk-17.0.13+11/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "./jdk-17.0.13+11/bin/java", Typeflag: tar.TypeReg, Mode: 0755},
+		{Name: "./jdk-17.0.13+11/release", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "./jdk-17.0.13+11/legal/release", Typeflag: tar.TypeLink, Linkname: "./jdk-17.0.13+11/release"},
+	}
+	mixedEntries := []tar.Header{
+		{Name: "jdk/bin/java", Typeflag: tar.TypeReg, Mode: 0755},
+		{Name: "README", Typeflag: tar.TypeReg, Mode: 0644},
+	}
+
+	tests := []struct {
+		name     string
+		entries  []tar.Header
+		strip    config.StripComponents
+		expected []string
+		home     string
+	}{
+		{"no strip", jdkEntries, 0, []string{"jdk-17.0.13+11/bin/java", "jdk-17.0.13+11/legal/release"}, "jdk-17.0.13+11"},
+		{"strip one", jdkEntries, 1, []string{"bin/java", "release", "legal/release"}, "."},
+		{"strip two", jdkEntries[:2], 2, []string{"java"}, "."},
+		{"auto single top-level directory", jdkEntries, config.StripAuto, []string{"bin/java", "legal/release"}, "."},
+		{"auto several top-level entries", mixedEntries, config.StripAuto, []string{"jdk/bin/java", "README"}, "."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := writeTarGz(t, tt.entries, map[string]string{
+				"./jdk-17.0.13+11/bin/java": "java",
+				"./jdk-17.0.13+11/release":  "JAVA_VERSION=17",
+			})
+			dest := t.TempDir()
+
+			extractor := downloader.NewExtractor()
+			extractor.SetStripComponents(tt.strip)
+			require.NoError(t, extractor.Extract(archive, dest))
+
+			for _, path := range tt.expected {
+				assert.FileExists(t, filepath.Join(dest, path))
+			}
+			home, err := downloader.FindHome(dest)
+			require.NoError(t, err)
+			assert.Equal(t, tt.home, home)
+		})
+	}
+}
+
+func TestExtractTarStripAutoSameNameInside(t *testing.T) {
+	// The top-level directory contains an entry of the same name
+	archive := writeTarGz(t, []tar.Header{
+		{Name: "gradle/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "gradle/gradle/wrapper.jar", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "gradle/bin/gradle", Typeflag: tar.TypeReg, Mode: 0755},
+	}, map[string]string{"gradle/bin/gradle": "#!/bin/sh"})
+	dest := t.TempDir()
+
+	extractor := downloader.NewExtractor()
+	extractor.SetStripComponents(config.StripAuto)
+	require.NoError(t, extractor.Extract(archive, dest))
+
+	assert.FileExists(t, filepath.Join(dest, "bin", "gradle"))
+	assert.FileExists(t, filepath.Join(dest, "gradle", "wrapper.jar"))
+	entries, err := os.ReadDir(dest)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "only the stripped content is left")
+}
+
+func TestExtractTarStripAutoRejectsLinkOutsideTop(t *testing.T) {
+	// Valid unstripped, the link would escape the destination once the directory is stripped
+	archive := writeTarGz(t, []tar.Header{
+		{Name: "jdk/bin/java", Typeflag: tar.TypeReg, Mode: 0755},
+		{Name: "jdk/escape", Typeflag: tar.TypeSymlink, Linkname: "../outside"},
+	}, nil)
+
+	extractor := downloader.NewExtractor()
+	extractor.SetStripComponents(config.StripAuto)
+	assert.ErrorContains(t, extractor.Extract(archive, t.TempDir()), "points outside destination")
+}
+
+func TestExtractTarStripAutoGitArchive(t *testing.T) {
+	// git archive starts with a global pax header, and a link may point to the top directory itself
+	archive := writeTarGz(t, []tar.Header{
+		{Name: "pax_global_header", Typeflag: tar.TypeXGlobalHeader, PAXRecords: map[string]string{"comment": "4fe6458"}},
+		{Name: "tool-1.0/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "tool-1.0/bin/tool", Typeflag: tar.TypeReg, Mode: 0755},
+		{Name: "tool-1.0/bin/home", Typeflag: tar.TypeSymlink, Linkname: ".."},
+	}, map[string]string{"tool-1.0/bin/tool": "binary"})
+	dest := t.TempDir()
+
+	extractor := downloader.NewExtractor()
+	extractor.SetStripComponents(config.StripAuto)
+	require.NoError(t, extractor.Extract(archive, dest))
+
+	assert.FileExists(t, filepath.Join(dest, "bin", "tool"))
+	assert.NoFileExists(t, filepath.Join(dest, "pax_global_header"))
+	home, err := filepath.EvalSymlinks(filepath.Join(dest, "bin", "home"))
+	require.NoError(t, err)
+	realDest, err := filepath.EvalSymlinks(dest)
+	require.NoError(t, err)
+	assert.Equal(t, realDest, home)
+}
+
+func TestExtractStopsWhenCancelled(t *testing.T) {
+	tarArchive := writeTarGz(t, []tar.Header{{Name: "bin/java", Typeflag: tar.TypeReg, Mode: 0755}}, nil)
+	zipArchive := writeZip(t, []zipEntry{{name: "bin/java", mode: 0755, content: "java"}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, archive := range []string{tarArchive, zipArchive} {
+		dest := t.TempDir()
+		extractor := downloader.NewExtractor()
+		extractor.SetContext(ctx)
+
+		assert.ErrorIs(t, extractor.Extract(archive, dest), context.Canceled)
+		assert.NoFileExists(t, filepath.Join(dest, "bin", "java"))
+	}
+}
+
+func TestExtractZipStripAuto(t *testing.T) {
+	archive := writeZip(t, []zipEntry{
+		{name: "gradle-8.5/bin/gradle", mode: 0755, content: "#!/bin/sh"},
+		{name: "gradle-8.5/lib/gradle.jar", mode: 0644, content: "jar"},
+	})
+	dest := t.TempDir()
+
+	extractor := downloader.NewExtractor()
+	extractor.SetStripComponents(config.StripAuto)
+	require.NoError(t, extractor.Extract(archive, dest))
+
+	assert.FileExists(t, filepath.Join(dest, "bin", "gradle"))
+	assert.FileExists(t, filepath.Join(dest, "lib", "gradle.jar"))
+}
+
+func TestHomeDirUsesRecordedHome(t *testing.T) {
+	installPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(installPath, "jdk-17", "bin"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(installPath, "extras"), 0755))
+
+	home, err := downloader.HomeDir(installPath, &downloader.SDKMetadata{HomePath: "jdk-17"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(installPath, "jdk-17"), home)
+
+	// Without metadata, several directories mean the installation itself is the home
+	home, err = downloader.HomeDir(installPath, nil)
+	require.NoError(t, err)
+	assert.Equal(t, installPath, home)
+}
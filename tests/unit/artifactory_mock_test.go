@@ -0,0 +1,145 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strigo/config"
+	"strigo/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockArtifactory serves a storage list response for /api/storage/generic-local/jdk/temurin
+func mockArtifactory(t *testing.T, checkAuth func(r *http.Request)) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checkAuth(r)
+		if r.URL.Path != "/artifactory/api/storage/generic-local/jdk/temurin" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Contains(t, r.URL.Query(), "list")
+		assert.Equal(t, "1", r.URL.Query().Get("deep"))
+
+		response := map[string]interface{}{
+			"uri": "http://" + r.Host + r.URL.Path,
+			"files": []map[string]interface{}{
+				{"uri": "/17", "folder": true},
+				{
+					"uri":    "/17/OpenJDK17U-jdk_x64_linux_hotspot_17.0.15_6.tar.gz",
+					"size":   190000000,
+					"folder": false,
+					"sha1":   "a9993e364706816aba3e25717850c26c9cd0d89d",
+					"sha2":   "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+				},
+				{
+					"uri":    "/21/OpenJDK21U-jdk_x64_linux_hotspot_21.0.9_10.tar.gz",
+					"size":   200000000,
+					"folder": false,
+					"sha1":   "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+				},
+				{"uri": "/README.txt", "size": 10, "folder": false},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func artifactoryRepo() config.SDKRepository {
+	return config.SDKRepository{
+		Type:       "jdk",
+		Registry:   "artifactory",
+		Repository: "generic-local",
+		Path:       "jdk/temurin",
+	}
+}
+
+func TestArtifactoryClientWithMockServer(t *testing.T) {
+	server := mockArtifactory(t, func(r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "deployer", user)
+		assert.Equal(t, "secret", pass)
+	})
+
+	registry := config.Registry{
+		Type:     "artifactory",
+		APIURL:   server.URL + "/artifactory/",
+		Username: "deployer",
+		Password: "secret",
+	}
+
+	assets, err := repository.FetchAvailableVersions(artifactoryRepo(), registry, "", true, "../../strigo-patterns.toml")
+	require.NoError(t, err)
+	require.Len(t, assets, 2)
+
+	byVersion := make(map[string]repository.SDKAsset)
+	for _, asset := range assets {
+		byVersion[asset.Version] = asset
+	}
+
+	jdk17 := byVersion["17.0.15_6"]
+	assert.Equal(t, server.URL+"/artifactory/generic-local/jdk/temurin/17/OpenJDK17U-jdk_x64_linux_hotspot_17.0.15_6.tar.gz", jdk17.DownloadUrl)
+	assert.Equal(t, int64(190000000), jdk17.Size)
+	assert.Equal(t, "sha256", jdk17.ChecksumAlgorithm)
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", jdk17.Checksum)
+
+	jdk21 := byVersion["21.0.9_10"]
+	assert.Equal(t, "sha1", jdk21.ChecksumAlgorithm)
+	assert.Equal(t, int64(200000000), jdk21.Size)
+}
+
+func TestArtifactoryClientTokenAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		registry config.Registry
+		check    func(t *testing.T, r *http.Request)
+	}{
+		{
+			name:     "bearer token",
+			registry: config.Registry{Token: "tok", Username: "ignored", Password: "ignored"},
+			check: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "Bearer tok", r.Header.Get("Authorization"))
+			},
+		},
+		{
+			name:     "api key",
+			registry: config.Registry{APIKey: "key"},
+			check: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "key", r.Header.Get("X-JFrog-Art-Api"))
+				assert.Empty(t, r.Header.Get("Authorization"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := mockArtifactory(t, func(r *http.Request) { tt.check(t, r) })
+			registry := tt.registry
+			registry.Type = "artifactory"
+			registry.APIURL = server.URL + "/artifactory"
+
+			assets, err := repository.FetchAvailableVersions(artifactoryRepo(), registry, "17", true, "../../strigo-patterns.toml")
+			require.NoError(t, err)
+			require.Len(t, assets, 1)
+			assert.Equal(t, "17.0.15_6", assets[0].Version)
+		})
+	}
+}
+
+func TestArtifactoryClientHTTPError(t *testing.T) {
+	server := mockArtifactory(t, func(r *http.Request) {})
+	registry := config.Registry{Type: "artifactory", APIURL: server.URL + "/artifactory"}
+
+	repo := artifactoryRepo()
+	repo.Path = "jdk/missing"
+	_, err := repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml")
+	assert.ErrorContains(t, err, "artifactory API returned 404")
+}
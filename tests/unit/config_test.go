@@ -0,0 +1,44 @@
+package unit
+
+import (
+	"strigo/config"
+	"testing"
+
+	"github.com/pelletier/go-toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidateArtifact(t *testing.T) {
+	cfg := &config.Config{SDKTypes: map[string]config.SDKType{
+		"tool": {Type: "tool", InstallDir: "tools", Artifact: config.ArtifactBinary},
+	}}
+	require.NoError(t, cfg.Validate())
+	assert.True(t, cfg.SDKTypes["tool"].IsBinary())
+
+	cfg.SDKTypes["tool"] = config.SDKType{Type: "tool", InstallDir: "tools", Artifact: "installer"}
+	assert.ErrorContains(t, cfg.Validate(), "invalid artifact")
+}
+
+func TestStripComponentsFromTOML(t *testing.T) {
+	var cfg config.Config
+	require.NoError(t, toml.Unmarshal([]byte(`
+[sdk_types]
+jdk = { type = "jdk", install_dir = "jdks", strip_components = "auto" }
+node = { type = "node", install_dir = "nodes" }
+
+[sdk_repositories]
+temurin = { registry = "nexus", type = "jdk", path = "jdk/temurin" }
+corretto = { registry = "nexus", type = "jdk", path = "jdk/corretto", strip_components = 2 }
+`), &cfg))
+
+	jdk := cfg.SDKTypes["jdk"]
+	assert.Equal(t, config.StripAuto, config.ResolveStripComponents(jdk, cfg.SDKRepositories["temurin"]))
+	assert.Equal(t, config.StripComponents(2), config.ResolveStripComponents(jdk, cfg.SDKRepositories["corretto"]))
+	assert.Equal(t, config.StripComponents(0), config.ResolveStripComponents(cfg.SDKTypes["node"], cfg.SDKRepositories["temurin"]))
+
+	for _, invalid := range []string{`"all"`, `-1`} {
+		err := toml.Unmarshal([]byte("[sdk_types]\njdk = { type = \"jdk\", strip_components = "+invalid+" }\n"), &cfg)
+		assert.Error(t, err, invalid)
+	}
+}
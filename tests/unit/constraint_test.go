@@ -0,0 +1,130 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strigo/downloader"
+	"strigo/repository/version"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var constraintCandidates = []string{
+	"8u442b06", "11.0.26_4", "17.0.12_7", "17.0.13_11", "17.1.0_1", "21.0.5_11", "21.0.6_7", "22.0.2_9", "24-ea+20",
+}
+
+// TestConstraintSelect tests resolving constraints and aliases to a concrete version
+func TestConstraintSelect(t *testing.T) {
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"latest", "22.0.2_9"},
+		{"17", "17.1.0_1"},
+		{"17.0", "17.0.13_11"},
+		{"17.0.x", "17.0.13_11"},
+		{"17.*", "17.1.0_1"},
+		{">=17 <21", "17.1.0_1"},
+		{">=17,<=21.0.5_11", "21.0.5_11"},
+		{">11.0.26_4 <17.0.13", "17.0.12_7"},
+		{"8", "8u442b06"},
+		{"17.0.13", "17.0.13_11"},
+		{"17.0.12", "17.0.12_7"},
+		{"17.0.14", ""},
+		{"17.0.13_11", "17.0.13_11"},
+		{"17.0.13+11", "17.0.13_11"},
+		{"24-ea+20", "24-ea+20"},
+		{"24", ""},
+		{"18", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			c, err := version.ParseConstraint(tt.constraint)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, c.Select(constraintCandidates, nil))
+		})
+	}
+}
+
+// TestConstraintLTS tests the lts alias with a caller-provided LTS predicate
+func TestConstraintLTS(t *testing.T) {
+	c, err := version.ParseConstraint("LTS")
+	require.NoError(t, err)
+	assert.True(t, c.LTS)
+
+	javaLTS := func(v version.Version) bool {
+		return v.Major() == 8 || v.Major() == 11 || v.Major() == 17 || v.Major() == 21
+	}
+	assert.Equal(t, "21.0.6_7", c.Select(constraintCandidates, javaLTS))
+}
+
+// TestParseConstraint tests constraint parsing
+func TestParseConstraint(t *testing.T) {
+	exact, err := version.ParseConstraint("21.0.5_11")
+	require.NoError(t, err)
+	assert.True(t, exact.IsExact())
+	assert.Equal(t, 21, exact.Major())
+
+	major, err := version.ParseConstraint("17")
+	require.NoError(t, err)
+	assert.False(t, major.IsExact())
+	assert.Equal(t, 17, major.Major())
+
+	release, err := version.ParseConstraint("17.0.13")
+	require.NoError(t, err)
+	assert.False(t, release.IsExact())
+	assert.Equal(t, 17, release.Major())
+
+	latest, err := version.ParseConstraint("latest")
+	require.NoError(t, err)
+	assert.Equal(t, 0, latest.Major())
+
+	for _, invalid := range []string{"", "~17", ">=abc", "x.y.x"} {
+		_, err := version.ParseConstraint(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+// TestConstraintSkipsInstalledVariants tests resolving a constraint against installed versions
+// as use does: variant builds recorded in the metadata are skipped, versions with a qualifier
+// such as Mandrel's are not
+func TestConstraintSkipsInstalledVariants(t *testing.T) {
+	install := func(basePath, name, variant string) {
+		installPath := filepath.Join(basePath, name)
+		require.NoError(t, os.MkdirAll(installPath, 0755))
+		require.NoError(t, downloader.SaveMetadata(installPath, downloader.SDKMetadata{Version: name, Variant: variant}))
+	}
+
+	zulu := filepath.Join(t.TempDir(), "zulu")
+	install(zulu, "21.0.4", "")
+	install(zulu, "21.0.5", "")
+	install(zulu, "21.0.5-crac", "crac")
+	install(zulu, "21.0.5-musl", "musl")
+	installed := []string{"21.0.4", "21.0.5", "21.0.5-crac", "21.0.5-musl"}
+
+	plain := downloader.PlainInstalls(zulu, installed)
+	assert.Equal(t, []string{"21.0.4", "21.0.5"}, plain)
+	for _, constraint := range []string{"21", "latest", "lts", "21.0.x"} {
+		c, err := version.ParseConstraint(constraint)
+		require.NoError(t, err)
+		assert.Equal(t, "21.0.5", c.Select(plain, nil), constraint)
+	}
+
+	mandrel := filepath.Join(t.TempDir(), "mandrel")
+	install(mandrel, "23.1.5.r21", "")
+	install(mandrel, "25.0.1.r25", "")
+	// Installed before metadata was written
+	require.NoError(t, os.MkdirAll(filepath.Join(mandrel, "24.2.1.r24"), 0755))
+	installed = []string{"23.1.5.r21", "24.2.1.r24", "25.0.1.r25"}
+
+	plain = downloader.PlainInstalls(mandrel, installed)
+	assert.Equal(t, installed, plain)
+	for constraint, expected := range map[string]string{"latest": "25.0.1.r25", "24": "24.2.1.r24", "23.1": "23.1.5.r21"} {
+		c, err := version.ParseConstraint(constraint)
+		require.NoError(t, err)
+		assert.Equal(t, expected, c.Select(plain, nil), constraint)
+	}
+}
@@ -0,0 +1,141 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strigo/config"
+	"strigo/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// discoPlatform returns the Disco operating system and architecture of the host
+func discoPlatform() (string, string) {
+	osName := map[string]string{"darwin": "macos"}[runtime.GOOS]
+	if osName == "" {
+		osName = runtime.GOOS
+	}
+	arch := map[string]string{"amd64": "x64", "arm64": "aarch64", "386": "x86"}[runtime.GOARCH]
+	if arch == "" {
+		arch = runtime.GOARCH
+	}
+	return osName, arch
+}
+
+// mockDisco serves Disco packages of the temurin distribution for the host platform
+func mockDisco(t *testing.T) *httptest.Server {
+	t.Helper()
+	osName, arch := discoPlatform()
+
+	pkg := func(host, id, javaVersion, archiveType, support string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":                    id,
+			"filename":              "OpenJDK-" + id + "." + archiveType,
+			"archive_type":          archiveType,
+			"distribution":          "temurin",
+			"java_version":          javaVersion,
+			"release_status":        "ga",
+			"term_of_support":       support,
+			"operating_system":      osName,
+			"architecture":          arch,
+			"package_type":          "jdk",
+			"size":                  190000000,
+			"directly_downloadable": true,
+			"links": map[string]string{
+				"pkg_info_uri":          host + "/disco/v3.0/ids/" + id,
+				"pkg_download_redirect": host + "/disco/v3.0/ids/" + id + "/redirect",
+			},
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := "http://" + r.Host
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/disco/v3.0/packages":
+			assert.Equal(t, "temurin", r.URL.Query().Get("distribution"))
+			assert.Equal(t, []string{"tar.gz", "zip"}, r.URL.Query()["archive_type"])
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"result": []map[string]interface{}{
+					pkg(host, "z21", "21.0.5+11", "zip", "lts"),
+					pkg(host, "t21", "21.0.5+11", "tar.gz", "lts"),
+					pkg(host, "t22", "22.0.2+9", "tar.gz", "sts"),
+				},
+				"message": "",
+			})
+		case "/disco/v3.0/ids/t21":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"result": []map[string]string{{
+					"checksum":      "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+					"checksum_type": "sha256",
+				}},
+			})
+		case "/disco/v3.0/ids/t22":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"result": []map[string]string{{
+					"checksum_type": "sha256",
+					"checksum_uri":  host + "/checksums/t22.sha256",
+				}},
+			})
+		case "/checksums/t22.sha256":
+			_, _ = w.Write([]byte("a9993e364706816aba3e25717850c26c9cd0d89d0000000000000000000000  OpenJDK22U.tar.gz\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDiscoClientWithMockServer(t *testing.T) {
+	server := mockDisco(t)
+	registry := config.Registry{Type: "disco", APIURL: server.URL + "/"}
+	repo := config.SDKRepository{Type: "jdk", Registry: "foojay", Distribution: "adoptium"}
+
+	assets, err := repository.FetchAvailableVersions(repo, registry, "", true)
+	require.NoError(t, err)
+	require.Len(t, assets, 2)
+
+	jdk21 := assets[1]
+	assert.Equal(t, "21.0.5_11", jdk21.Version)
+	assert.Equal(t, "tar.gz", jdk21.ArchiveType)
+	assert.Equal(t, server.URL+"/disco/v3.0/ids/t21/redirect", jdk21.DownloadUrl)
+	assert.Equal(t, "OpenJDK-t21.tar.gz", jdk21.ArchiveName)
+	assert.Equal(t, int64(190000000), jdk21.Size)
+	assert.True(t, jdk21.LTS)
+	assert.False(t, assets[0].LTS)
+
+	// The checksum comes from the package info
+	require.NoError(t, repository.ResolveChecksum(&jdk21, registry))
+	assert.Equal(t, "sha256", jdk21.ChecksumAlgorithm)
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", jdk21.Checksum)
+
+	// Or from the checksum file the package info points to
+	jdk22 := assets[0]
+	require.NoError(t, repository.ResolveChecksum(&jdk22, registry))
+	assert.Equal(t, "a9993e364706816aba3e25717850c26c9cd0d89d0000000000000000000000", jdk22.Checksum)
+}
+
+func TestDiscoClientDefaultsToRepositoryName(t *testing.T) {
+	server := mockDisco(t)
+	registry := config.Registry{Type: "disco", APIURL: server.URL}
+
+	// Without distribution, the repository name is sent
+	repo := config.SDKRepository{Name: "temurin", Type: "jdk", Registry: "foojay"}
+	assets, err := repository.FetchAvailableVersions(repo, registry, "", true)
+	require.NoError(t, err)
+	assert.Len(t, assets, 2)
+}
+
+func TestDiscoDistributionMapping(t *testing.T) {
+	assert.Equal(t, "temurin", repository.DiscoDistribution("Temurin"))
+	assert.Equal(t, "temurin", repository.DiscoDistribution("adoptium"))
+	assert.Equal(t, "sap_machine", repository.DiscoDistribution("sapmachine"))
+	assert.Equal(t, "oracle_open_jdk", repository.DiscoDistribution("openjdk"))
+	assert.Equal(t, "zulu", repository.DiscoDistribution("zulu"))
+}
@@ -0,0 +1,306 @@
+package unit
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strigo/downloader"
+	"strigo/downloader/core"
+	"strigo/downloader/progress"
+	"strigo/repository"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fileModTime is the modification time advertised by test archive servers
+var fileModTime = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+// buildTarGz creates an in-memory tar.gz archive from a map of file names to contents
+func buildTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	return buf.Bytes()
+}
+
+// serveArchive starts a test server serving data at /<name>
+func serveArchive(t *testing.T, name string, data []byte) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+name {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, name, fileModTime, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPreferredChecksum(t *testing.T) {
+	algo, digest := repository.PreferredChecksum(map[string]string{
+		"md5":    "m",
+		"sha1":   "s1",
+		"sha256": "s256",
+	})
+	assert.Equal(t, "sha256", algo)
+	assert.Equal(t, "s256", digest)
+
+	algo, digest = repository.PreferredChecksum(map[string]string{"sha512": "s512", "sha256": "s256"})
+	assert.Equal(t, "sha512", algo)
+	assert.Equal(t, "s512", digest)
+
+	algo, digest = repository.PreferredChecksum(nil)
+	assert.Empty(t, algo)
+	assert.Empty(t, digest)
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	assert.NoError(t, core.VerifyChecksum("sha256", "ABCDEF", "abcdef"))
+
+	err := core.VerifyChecksum("sha256", "abcdef", "012345")
+	require.Error(t, err)
+	var mismatch *core.ChecksumMismatchError
+	assert.ErrorAs(t, err, &mismatch)
+}
+
+func TestDownloadAndExtractVerifiesChecksum(t *testing.T) {
+	archive := buildTarGz(t, map[string]string{"jdk-17/release": "JAVA_VERSION=17"})
+	sum := sha256.Sum256(archive)
+	server := serveArchive(t, "jdk.tar.gz", archive)
+
+	tmpDir := t.TempDir()
+	opts := core.DownloadOptions{
+		DownloadURL:       server.URL + "/jdk.tar.gz",
+		CacheDir:          filepath.Join(tmpDir, "cache"),
+		InstallPath:       filepath.Join(tmpDir, "sdks", "17"),
+		SDKType:           "jdk",
+		Distribution:      "temurin",
+		Version:           "17",
+		Checksum:          hex.EncodeToString(sum[:]),
+		ChecksumAlgorithm: "sha256",
+	}
+	require.NoError(t, os.MkdirAll(opts.CacheDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(opts.InstallPath), 0755))
+
+	result, err := downloader.NewManager().DownloadAndExtract(opts)
+	require.NoError(t, err)
+	assert.True(t, result.Verified)
+	assert.Equal(t, opts.Checksum, result.Checksum)
+	assert.FileExists(t, filepath.Join(opts.InstallPath, "jdk-17", "release"))
+}
+
+func TestDownloadAndExtractCachesUnderFileName(t *testing.T) {
+	// Redirect URLs such as Disco's do not end with the archive name
+	archive := buildTarGz(t, map[string]string{"jdk-21/release": "JAVA_VERSION=21"})
+	server := serveArchive(t, "redirect", archive)
+
+	tmpDir := t.TempDir()
+	opts := core.DownloadOptions{
+		DownloadURL:  server.URL + "/redirect",
+		FileName:     "OpenJDK21U-jdk_x64_linux_hotspot_21.0.5_11.tar.gz",
+		CacheDir:     filepath.Join(tmpDir, "cache"),
+		InstallPath:  filepath.Join(tmpDir, "sdks", "21"),
+		SDKType:      "jdk",
+		Distribution: "temurin",
+		Version:      "21",
+		KeepCache:    true,
+	}
+	require.NoError(t, os.MkdirAll(opts.CacheDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(opts.InstallPath), 0755))
+
+	_, err := downloader.NewManager().DownloadAndExtract(opts)
+	require.NoError(t, err)
+	cacheDir := filepath.Join(opts.CacheDir, "jdk", "temurin", "21")
+	assert.FileExists(t, filepath.Join(cacheDir, opts.FileName))
+	assert.NoFileExists(t, filepath.Join(cacheDir, "redirect"))
+
+	// Names with a path are not trusted
+	opts.FileName = "../escaped.tar.gz"
+	opts.InstallPath = filepath.Join(tmpDir, "sdks", "21-again")
+	_, err = downloader.NewManager().DownloadAndExtract(opts)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(cacheDir, "redirect"))
+	assert.NoFileExists(t, filepath.Join(opts.CacheDir, "jdk", "temurin", "escaped.tar.gz"))
+}
+
+func TestDownloadAndExtractChecksumMismatch(t *testing.T) {
+	archive := buildTarGz(t, map[string]string{"jdk-17/release": "JAVA_VERSION=17"})
+	server := serveArchive(t, "jdk.tar.gz", archive)
+
+	tmpDir := t.TempDir()
+	opts := core.DownloadOptions{
+		DownloadURL:       server.URL + "/jdk.tar.gz",
+		CacheDir:          filepath.Join(tmpDir, "cache"),
+		InstallPath:       filepath.Join(tmpDir, "sdks", "17"),
+		SDKType:           "jdk",
+		Distribution:      "temurin",
+		Version:           "17",
+		KeepCache:         true,
+		Checksum:          "0000000000000000000000000000000000000000",
+		ChecksumAlgorithm: "sha1",
+	}
+	require.NoError(t, os.MkdirAll(opts.CacheDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(opts.InstallPath), 0755))
+
+	_, err := downloader.NewManager().DownloadAndExtract(opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+
+	// Nothing extracted, archive moved out of the regular cache
+	assert.NoDirExists(t, filepath.Join(opts.InstallPath, "jdk-17"))
+	assert.NoFileExists(t, filepath.Join(opts.CacheDir, "jdk", "temurin", "17", "jdk.tar.gz"))
+	quarantined, err := os.ReadDir(filepath.Join(opts.CacheDir, "quarantine"))
+	require.NoError(t, err)
+	assert.Len(t, quarantined, 1)
+
+	// --skip-verify lets the same download through
+	opts.SkipVerify = true
+	result, err := downloader.NewManager().DownloadAndExtract(opts)
+	require.NoError(t, err)
+	assert.False(t, result.Verified)
+}
+
+func TestDownloadAndExtractReportsProgress(t *testing.T) {
+	archive := buildTarGz(t, map[string]string{"node-v22/bin/node": strings.Repeat("x", 64*1024)})
+	server := serveArchive(t, "node.tar.gz", archive)
+
+	tmpDir := t.TempDir()
+	opts := core.DownloadOptions{
+		DownloadURL:  server.URL + "/node.tar.gz",
+		CacheDir:     filepath.Join(tmpDir, "cache"),
+		InstallPath:  filepath.Join(tmpDir, "sdks", "22"),
+		SDKType:      "node",
+		Distribution: "nodejs",
+		Version:      "22.13.1",
+	}
+	require.NoError(t, os.MkdirAll(opts.CacheDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(opts.InstallPath), 0755))
+
+	var events bytes.Buffer
+	manager := downloader.NewManager()
+	manager.SetProgressReporter(progress.NewJSONReporter(&events))
+
+	_, err := manager.DownloadAndExtract(opts)
+	require.NoError(t, err)
+
+	// Each line is a standalone JSON event
+	done := map[string]progress.Event{}
+	for _, line := range strings.Split(strings.TrimSpace(events.String()), "\n") {
+		var event progress.Event
+		require.NoError(t, json.Unmarshal([]byte(line), &event), "invalid NDJSON line: %s", line)
+		assert.Equal(t, "progress", event.Type)
+		if event.State == "done" {
+			done[event.Phase] = event
+		}
+	}
+
+	require.Contains(t, done, progress.PhaseDownload)
+	require.Contains(t, done, progress.PhaseExtract)
+	assert.Equal(t, int64(len(archive)), done[progress.PhaseDownload].Current)
+	assert.Equal(t, int64(len(archive)), done[progress.PhaseDownload].Total)
+	assert.InDelta(t, 100.0, done[progress.PhaseDownload].Percent, 0.01)
+	assert.Equal(t, int64(len(archive)), done[progress.PhaseExtract].Total)
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", progress.FormatBytes(512))
+	assert.Equal(t, "1.5 KiB", progress.FormatBytes(1536))
+	assert.Equal(t, "200.0 MiB", progress.FormatBytes(200*1024*1024))
+}
+
+func TestDownloadAndExtractInstallsBinary(t *testing.T) {
+	server := serveArchive(t, "kubectl", []byte("\x7fELF binary"))
+
+	tmpDir := t.TempDir()
+	opts := core.DownloadOptions{
+		DownloadURL:  server.URL + "/kubectl",
+		CacheDir:     filepath.Join(tmpDir, "cache"),
+		InstallPath:  filepath.Join(tmpDir, "sdks", "1.29.0"),
+		SDKType:      "tool",
+		Distribution: "kubectl",
+		Version:      "1.29.0",
+		Binary:       true,
+		BinaryName:   "kubectl",
+	}
+	require.NoError(t, os.MkdirAll(opts.CacheDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(opts.InstallPath), 0755))
+
+	_, err := downloader.NewManager().DownloadAndExtract(opts)
+	require.NoError(t, err)
+
+	binary := filepath.Join(opts.InstallPath, "bin", "kubectl")
+	info, err := os.Stat(binary)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	data, err := os.ReadFile(binary)
+	require.NoError(t, err)
+	assert.Equal(t, "\x7fELF binary", string(data))
+}
+
+func TestInstallBinaryRejectsInvalidName(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "tool")
+	require.NoError(t, os.WriteFile(src, []byte("x"), 0644))
+
+	for _, name := range []string{"", "..", "../evil"} {
+		assert.Error(t, downloader.InstallBinary(src, t.TempDir(), name), "name %q", name)
+	}
+}
+
+func TestDownloadAndExtractSkipsHeadWithKnownSize(t *testing.T) {
+	archive := buildTarGz(t, map[string]string{"jdk-17/release": "JAVA_VERSION=17"})
+	var heads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			heads++
+		}
+		http.ServeContent(w, r, "jdk.tar.gz", fileModTime, bytes.NewReader(archive))
+	}))
+	t.Cleanup(server.Close)
+
+	tmpDir := t.TempDir()
+	opts := core.DownloadOptions{
+		DownloadURL:  server.URL + "/jdk.tar.gz",
+		Size:         int64(len(archive)),
+		CacheDir:     filepath.Join(tmpDir, "cache"),
+		InstallPath:  filepath.Join(tmpDir, "sdks", "17"),
+		SDKType:      "jdk",
+		Distribution: "temurin",
+		Version:      "17",
+	}
+	require.NoError(t, os.MkdirAll(opts.CacheDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(opts.InstallPath), 0755))
+
+	_, err := downloader.NewManager().DownloadAndExtract(opts)
+	require.NoError(t, err)
+	assert.Zero(t, heads)
+	assert.FileExists(t, filepath.Join(opts.InstallPath, "jdk-17", "release"))
+}
@@ -0,0 +1,97 @@
+package unit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strigo/config"
+	"strigo/downloader"
+	"strigo/downloader/core"
+	"strigo/downloader/network"
+	"strigo/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFilesystemRegistry lays out a Temurin archive with a checksum sidecar below root
+func writeFilesystemRegistry(t *testing.T, root string) (archivePath, digest string) {
+	t.Helper()
+
+	archive := buildTarGz(t, map[string]string{"jdk-17/release": "JAVA_VERSION=17"})
+	sum := sha256.Sum256(archive)
+	digest = hex.EncodeToString(sum[:])
+
+	dir := filepath.Join(root, "jdk", "temurin", "17")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	archivePath = filepath.Join(dir, "OpenJDK17U-jdk_x64_linux_hotspot_17.0.15_6.tar.gz")
+	require.NoError(t, os.WriteFile(archivePath, archive, 0644))
+	require.NoError(t, os.WriteFile(archivePath+".sha256", []byte(digest+"  OpenJDK17U-jdk_x64_linux_hotspot_17.0.15_6.tar.gz\n"), 0644))
+	// Hidden files are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".OpenJDK17U-jdk_x64_linux_hotspot_17.0.99_1.tar.gz"), []byte("x"), 0644))
+	return archivePath, digest
+}
+
+func TestFilesystemRegistry(t *testing.T) {
+	root := t.TempDir()
+	archivePath, digest := writeFilesystemRegistry(t, root)
+
+	repo := config.SDKRepository{Type: "jdk", Registry: "local", Path: "jdk/temurin"}
+
+	for _, apiURL := range []string{root, network.FileURL(root)} {
+		registry := config.Registry{Type: "filesystem", APIURL: apiURL}
+
+		assets, err := repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml")
+		require.NoError(t, err, apiURL)
+		require.Len(t, assets, 1)
+
+		asset := assets[0]
+		assert.Equal(t, "17.0.15_6", asset.Version)
+		assert.Equal(t, network.FileURL(archivePath), asset.DownloadUrl)
+		assert.Equal(t, "sha256", asset.ChecksumAlgorithm)
+		assert.Equal(t, digest, asset.Checksum)
+		assert.Positive(t, asset.Size)
+	}
+}
+
+func TestFilesystemRegistryMissingPath(t *testing.T) {
+	registry := config.Registry{Type: "filesystem", APIURL: t.TempDir()}
+	repo := config.SDKRepository{Type: "jdk", Path: "jdk/missing"}
+
+	_, err := repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestDownloadAndExtractFromFileURL(t *testing.T) {
+	tmpDir := t.TempDir()
+	archivePath, digest := writeFilesystemRegistry(t, filepath.Join(tmpDir, "share"))
+
+	opts := core.DownloadOptions{
+		DownloadURL:       network.FileURL(archivePath),
+		CacheDir:          filepath.Join(tmpDir, "cache"),
+		InstallPath:       filepath.Join(tmpDir, "sdks", "17"),
+		SDKType:           "jdk",
+		Distribution:      "temurin",
+		Version:           "17",
+		KeepCache:         true,
+		Checksum:          digest,
+		ChecksumAlgorithm: "sha256",
+	}
+	require.NoError(t, os.MkdirAll(opts.CacheDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(opts.InstallPath), 0755))
+
+	result, err := downloader.NewManager().DownloadAndExtract(opts)
+	require.NoError(t, err)
+	assert.True(t, result.Verified)
+	assert.FileExists(t, filepath.Join(opts.InstallPath, "jdk-17", "release"))
+
+	// Same filesystem: the cache entry is a hardlink to the registry file
+	cached := filepath.Join(opts.CacheDir, "jdk", "temurin", "17", filepath.Base(archivePath))
+	cachedInfo, err := os.Stat(cached)
+	require.NoError(t, err)
+	sourceInfo, err := os.Stat(archivePath)
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(cachedInfo, sourceInfo))
+}
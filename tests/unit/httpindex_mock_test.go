@@ -0,0 +1,129 @@
+package unit
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strigo/config"
+	"strigo/repository"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// autoindexPage renders an nginx-style directory listing
+func autoindexPage(path string, hrefs ...string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<html><head><title>Index of %s</title></head><body><h1>Index of %s</h1><hr><pre>", path, path)
+	b.WriteString(`<a href="../">../</a>` + "\n")
+	b.WriteString(`<a href="?C=N;O=D">Name</a>` + "\n")
+	for _, href := range hrefs {
+		fmt.Fprintf(&b, "<a href=\"%s\">%s</a>    01-Jan-2024 00:00    -\n", href, href)
+	}
+	b.WriteString("</pre><hr></body></html>")
+	return b.String()
+}
+
+// mockAutoindex serves a directory tree of autoindex pages under /pub/
+func mockAutoindex(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	pages := map[string]string{
+		"/pub/jdk/temurin/": autoindexPage("/pub/jdk/temurin/", "17/", "21/", "nightly/", "https://elsewhere.example.com/jdk-22.tar.gz"),
+		"/pub/jdk/temurin/17/": autoindexPage("/pub/jdk/temurin/17/",
+			"OpenJDK17U-jdk_x64_linux_hotspot_17.0.15_6.tar.gz",
+			"OpenJDK17U-jdk_x64_linux_hotspot_17.0.15_6.tar.gz.sha256"),
+		// Absolute hrefs are common in Apache listings
+		"/pub/jdk/temurin/21/": autoindexPage("/pub/jdk/temurin/21/",
+			"/pub/jdk/temurin/21/OpenJDK21U-jdk_x64_linux_hotspot_21.0.9_10.tar.gz",
+			"/pub/jdk/temurin/"),
+		"/pub/jdk/temurin/nightly/":      autoindexPage("/pub/jdk/temurin/nightly/", "deep/"),
+		"/pub/jdk/temurin/nightly/deep/": autoindexPage("/pub/jdk/temurin/nightly/deep/", "OpenJDK23U-jdk_x64_linux_hotspot_23.0.1_11.tar.gz"),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(page))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPIndexClient(t *testing.T) {
+	server := mockAutoindex(t)
+
+	registry := config.Registry{Type: "http-index", APIURL: server.URL + "/pub"}
+	repo := config.SDKRepository{Type: "jdk", Registry: "mirror", Path: "jdk/temurin"}
+
+	assets, err := repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml")
+	require.NoError(t, err)
+
+	urls := make(map[string]string)
+	for _, asset := range assets {
+		urls[asset.Version] = asset.DownloadUrl
+	}
+	assert.Equal(t, map[string]string{
+		"17.0.15_6": server.URL + "/pub/jdk/temurin/17/OpenJDK17U-jdk_x64_linux_hotspot_17.0.15_6.tar.gz",
+		"21.0.9_10": server.URL + "/pub/jdk/temurin/21/OpenJDK21U-jdk_x64_linux_hotspot_21.0.9_10.tar.gz",
+		"23.0.1_11": server.URL + "/pub/jdk/temurin/nightly/deep/OpenJDK23U-jdk_x64_linux_hotspot_23.0.1_11.tar.gz",
+	}, urls)
+}
+
+func TestHTTPIndexClientMaxDepth(t *testing.T) {
+	server := mockAutoindex(t)
+
+	depth := 1
+	registry := config.Registry{Type: "http-index", APIURL: server.URL + "/pub/", MaxDepth: &depth}
+	repo := config.SDKRepository{Type: "jdk", Registry: "mirror", Path: "/jdk/temurin/"}
+
+	assets, err := repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml")
+	require.NoError(t, err)
+
+	var versions []string
+	for _, asset := range assets {
+		versions = append(versions, asset.Version)
+	}
+	assert.ElementsMatch(t, []string{"17.0.15_6", "21.0.9_10"}, versions)
+}
+
+func TestHTTPIndexClientMissingPath(t *testing.T) {
+	server := mockAutoindex(t)
+
+	registry := config.Registry{Type: "http-index", APIURL: server.URL + "/pub"}
+	repo := config.SDKRepository{Type: "jdk", Path: "jdk/missing"}
+
+	_, err := repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml")
+	assert.ErrorContains(t, err, "returned 404")
+}
+
+func TestHTTPIndexClientNumericEntities(t *testing.T) {
+	// JEP 223 directory names such as jdk-21.0.5+11 are listed with encoded "+"
+	pages := map[string]string{
+		"/pub/jdk/temurin/":               autoindexPage("/pub/jdk/temurin/", "jdk-21.0.5&#43;11/"),
+		"/pub/jdk/temurin/jdk-21.0.5+11/": autoindexPage("/pub/jdk/temurin/jdk-21.0.5+11/", "OpenJDK21U-jdk_x64_linux_hotspot_21.0.5_11.tar.gz"),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	registry := config.Registry{Type: "http-index", APIURL: server.URL + "/pub"}
+	repo := config.SDKRepository{Type: "jdk", Path: "jdk/temurin"}
+
+	assets, err := repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml", repository.Platform{OS: "linux", Arch: "amd64"})
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	assert.Equal(t, server.URL+"/pub/jdk/temurin/jdk-21.0.5+11/OpenJDK21U-jdk_x64_linux_hotspot_21.0.5_11.tar.gz", assets[0].DownloadUrl)
+}
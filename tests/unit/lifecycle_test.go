@@ -0,0 +1,131 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strigo/config"
+	"strigo/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lifecycleTOML = `
+[jdk.default.17]
+lts = true
+eol = "2029-10-31"
+
+[jdk.default.22]
+lts = false
+eol = "2024-09-17"
+
+[jdk.corretto.17]
+lts = true
+eol = "2030-10-31"
+`
+
+func TestParseLifecycle(t *testing.T) {
+	lifecycle, err := repository.ParseLifecycle([]byte(lifecycleTOML), "toml")
+	require.NoError(t, err)
+
+	// Distribution entries take precedence over the default ones
+	entry, ok := lifecycle.Lookup("jdk", "corretto", 17)
+	require.True(t, ok)
+	assert.Equal(t, "2030-10-31", entry.EOL)
+
+	entry, ok = lifecycle.Lookup("jdk", "temurin", 17)
+	require.True(t, ok)
+	assert.True(t, entry.LTS)
+	assert.Equal(t, "2029-10-31", entry.EOL)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.False(t, entry.IsEOL(now))
+	entry, _ = lifecycle.Lookup("jdk", "temurin", 22)
+	assert.True(t, entry.IsEOL(now))
+
+	_, ok = lifecycle.Lookup("jdk", "temurin", 9)
+	assert.False(t, ok)
+	_, ok = lifecycle.Lookup("node", "nodejs", 22)
+	assert.False(t, ok)
+
+	jsonData := `{"node": {"default": {"22": {"lts": true, "support_end": "2025-10-21", "eol": "2027-04-30"}}}}`
+	lifecycle, err = repository.ParseLifecycle([]byte(jsonData), "json")
+	require.NoError(t, err)
+	entry, ok = lifecycle.Lookup("node", "nodejs", 22)
+	require.True(t, ok)
+	assert.True(t, entry.IsSupportEnded(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	_, err = repository.ParseLifecycle([]byte("[jdk.default.17]\neol = \"31/10/2029\"\n"), "toml")
+	assert.ErrorContains(t, err, "invalid eol")
+	_, err = repository.ParseLifecycle([]byte("[jdk.default.latest]\nlts = true\n"), "toml")
+	assert.ErrorContains(t, err, "invalid major version")
+}
+
+func TestShippedLifecycleFile(t *testing.T) {
+	data, err := os.ReadFile("../../strigo-lifecycle.toml")
+	require.NoError(t, err)
+	lifecycle, err := repository.ParseLifecycle(data, "toml")
+	require.NoError(t, err)
+
+	entry, ok := lifecycle.Lookup("jdk", "temurin", 21)
+	require.True(t, ok)
+	assert.True(t, entry.LTS)
+}
+
+func TestLoadLifecycleFromURL(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		user, pass, _ := r.BasicAuth()
+		assert.Equal(t, "reader", user)
+		assert.Equal(t, "secret", pass)
+		_, _ = w.Write([]byte(lifecycleTOML))
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			CacheDir:          cacheDir,
+			LifecycleURL:      server.URL + "/strigo/lifecycle.toml",
+			LifecycleRegistry: "nexus",
+		},
+		Registries: map[string]config.Registry{"nexus": {Type: "nexus", Username: "reader", Password: "secret"}},
+	}
+
+	lifecycle, err := repository.LoadLifecycle(cfg)
+	require.NoError(t, err)
+	_, ok := lifecycle.Lookup("jdk", "temurin", 17)
+	assert.True(t, ok)
+	assert.FileExists(t, filepath.Join(cacheDir, "lifecycle.toml"))
+
+	// Fresh cache, no request
+	_, err = repository.LoadLifecycle(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, hits)
+
+	// Expired cache and unreachable URL: the stale cache is used
+	server.Close()
+	cfg.General.LifecycleTTL = "0s"
+	zero := 0
+	nexus := cfg.Registries["nexus"]
+	nexus.MaxRetries = &zero
+	cfg.Registries["nexus"] = nexus
+	lifecycle, err = repository.LoadLifecycle(cfg)
+	require.NoError(t, err)
+	_, ok = lifecycle.Lookup("jdk", "temurin", 17)
+	assert.True(t, ok)
+}
+
+func TestLoadLifecycleNotConfigured(t *testing.T) {
+	lifecycle, err := repository.LoadLifecycle(&config.Config{})
+	require.NoError(t, err)
+	assert.Nil(t, lifecycle)
+
+	_, ok := lifecycle.Lookup("jdk", "temurin", 17)
+	assert.False(t, ok)
+}
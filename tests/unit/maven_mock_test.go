@@ -0,0 +1,105 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strigo/config"
+	"strigo/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mavenMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>org.apache.maven</groupId>
+  <artifactId>apache-maven</artifactId>
+  <versioning>
+    <latest>3.9.9</latest>
+    <release>3.9.9</release>
+    <versions>
+      <version>3.8.8</version>
+      <version>3.9.6</version>
+      <version>3.9.9</version>
+    </versions>
+  </versioning>
+</metadata>`
+
+// mockMaven serves a maven2 repository at /repository/maven-releases with sidecars for 3.9.9 only
+func mockMaven(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	files := map[string]string{
+		"/repository/maven-releases/org/apache/maven/apache-maven/maven-metadata.xml":                       mavenMetadata,
+		"/repository/maven-releases/org/apache/maven/apache-maven/3.9.9/apache-maven-3.9.9-bin.tar.gz.sha1": "a9993e364706816aba3e25717850c26c9cd0d89d\n",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func mavenRepo() config.SDKRepository {
+	return config.SDKRepository{
+		Type:       "maven",
+		Registry:   "maven",
+		Repository: "maven-releases",
+		GroupID:    "org.apache.maven",
+		ArtifactID: "apache-maven",
+		Classifier: "bin",
+	}
+}
+
+func TestMavenClientWithMockServer(t *testing.T) {
+	server := mockMaven(t)
+	registry := config.Registry{Type: "maven", APIURL: server.URL + "/repository/{repository}/"}
+
+	assets, err := repository.FetchAvailableVersions(mavenRepo(), registry, "3.9", true)
+	require.NoError(t, err)
+	require.Len(t, assets, 2)
+	assert.Equal(t, "3.9.9", assets[0].Version)
+	assert.Equal(t, "3.9.6", assets[1].Version)
+
+	latest := assets[0]
+	assert.Equal(t, server.URL+"/repository/maven-releases/org/apache/maven/apache-maven/3.9.9/apache-maven-3.9.9-bin.tar.gz", latest.DownloadUrl)
+	assert.Empty(t, latest.Checksum)
+
+	// Only the sha1 sidecar exists, the missing sha256 one is skipped
+	require.NoError(t, repository.ResolveChecksum(&latest, registry))
+	assert.Equal(t, "sha1", latest.ChecksumAlgorithm)
+	assert.Equal(t, "a9993e364706816aba3e25717850c26c9cd0d89d", latest.Checksum)
+
+	// No sidecar at all leaves the asset unverified
+	older := assets[1]
+	require.NoError(t, repository.ResolveChecksum(&older, registry))
+	assert.Empty(t, older.Checksum)
+}
+
+func TestMavenClientDefaultExtension(t *testing.T) {
+	server := mockMaven(t)
+	registry := config.Registry{Type: "maven", APIURL: server.URL + "/repository/{repository}"}
+
+	repo := mavenRepo()
+	repo.Classifier = ""
+	assets, err := repository.FetchAvailableVersions(repo, registry, "3.8.8", true)
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	assert.Equal(t, server.URL+"/repository/maven-releases/org/apache/maven/apache-maven/3.8.8/apache-maven-3.8.8.tar.gz", assets[0].DownloadUrl)
+}
+
+func TestMavenClientMissingArtifact(t *testing.T) {
+	server := mockMaven(t)
+	registry := config.Registry{Type: "maven", APIURL: server.URL + "/repository/{repository}"}
+
+	repo := mavenRepo()
+	repo.ArtifactID = "gradle"
+	_, err := repository.FetchAvailableVersions(repo, registry, "", true)
+	assert.ErrorContains(t, err, "maven repository returned 404")
+}
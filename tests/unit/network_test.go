@@ -0,0 +1,339 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strigo/config"
+	"strigo/downloader/network"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noRetrySettings returns default network settings with retries disabled
+func noRetrySettings() network.Settings {
+	settings := network.DefaultSettings()
+	settings.Retry.MaxRetries = 0
+	return settings
+}
+
+// fastRetrySettings returns default network settings with short backoff delays
+func fastRetrySettings(maxRetries int) network.Settings {
+	settings := network.DefaultSettings()
+	settings.Retry = network.RetryPolicy{
+		MaxRetries:     maxRetries,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	}
+	return settings
+}
+
+// flakyServer serves data but aborts the first full (non-range) response halfway through
+func flakyServer(t *testing.T, data []byte, acceptRanges bool) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var rangeRequests int32
+	var aborted int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "" {
+			atomic.AddInt32(&rangeRequests, 1)
+		}
+
+		if !acceptRanges {
+			r.Header.Del("Range")
+		}
+
+		if r.Header.Get("Range") == "" && atomic.CompareAndSwapInt32(&aborted, 0, 1) {
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.Header().Set("Last-Modified", fileModTime.Format(http.TimeFormat))
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(data[:len(data)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+
+		if !acceptRanges {
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			_, _ = w.Write(data)
+			return
+		}
+		http.ServeContent(w, r, "jdk.tar.gz", fileModTime, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+	return server, &rangeRequests
+}
+
+func TestDownloadFileResumesPartialDownload(t *testing.T) {
+	data := bytes.Repeat([]byte("strigo-resume-"), 4096)
+	sum := sha256.Sum256(data)
+	server, rangeRequests := flakyServer(t, data, true)
+
+	dest := filepath.Join(t.TempDir(), "jdk.tar.gz")
+	client := network.NewClientWithSettings("", "", noRetrySettings())
+
+	// First attempt is interrupted and leaves a partial file behind
+	_, err := client.DownloadFile(server.URL+"/jdk.tar.gz", dest, "sha256")
+	require.Error(t, err)
+	assert.NoFileExists(t, dest)
+	assert.FileExists(t, dest+network.PartSuffix)
+
+	// Second attempt continues where the first one stopped
+	digest, err := client.DownloadFile(server.URL+"/jdk.tar.gz", dest, "sha256")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(rangeRequests))
+	assert.Equal(t, hex.EncodeToString(sum[:]), digest)
+	assert.NoFileExists(t, dest+network.PartSuffix)
+
+	content, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, data, content)
+}
+
+func TestDownloadFileFallsBackWithoutRangeSupport(t *testing.T) {
+	data := bytes.Repeat([]byte("strigo-full-"), 4096)
+	server, _ := flakyServer(t, data, false)
+
+	dest := filepath.Join(t.TempDir(), "jdk.tar.gz")
+	client := network.NewClientWithSettings("", "", noRetrySettings())
+
+	_, err := client.DownloadFile(server.URL+"/jdk.tar.gz", dest, "")
+	require.Error(t, err)
+
+	// Server ignores the Range header and sends the full file with 200
+	_, err = client.DownloadFile(server.URL+"/jdk.tar.gz", dest, "")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, data, content)
+}
+
+func TestDownloadFileRetriesAndResumesAutomatically(t *testing.T) {
+	data := bytes.Repeat([]byte("strigo-retry-"), 4096)
+	server, rangeRequests := flakyServer(t, data, true)
+
+	dest := filepath.Join(t.TempDir(), "jdk.tar.gz")
+	client := network.NewClientWithSettings("", "", fastRetrySettings(2))
+
+	_, err := client.DownloadFile(server.URL+"/jdk.tar.gz", dest, "")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(rangeRequests))
+
+	content, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, data, content)
+}
+
+// writePartial leaves a partial download of dest behind, with the given resume state
+func writePartial(t *testing.T, dest string, data []byte, state string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(dest+network.PartSuffix, data, 0644))
+	require.NoError(t, os.WriteFile(dest+network.PartSuffix+".json", []byte(state), 0644))
+}
+
+func TestDownloadFileDoesNotResumeWithWeakETagOnly(t *testing.T) {
+	data := bytes.Repeat([]byte("strigo-weak-"), 4096)
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "jdk.tar.gz", fileModTime, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+
+	dest := filepath.Join(t.TempDir(), "jdk.tar.gz")
+	url := server.URL + "/jdk.tar.gz"
+	// A weak ETag cannot be sent in If-Range and there is no Last-Modified to fall back to
+	writePartial(t, dest, []byte("stale"), `{"url": "`+url+`", "etag": "W/\"1\"", "accept_ranges": true}`)
+
+	_, err := network.NewClientWithSettings("", "", noRetrySettings()).DownloadFile(url, dest, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{""}, ranges)
+
+	content, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, data, content)
+}
+
+func TestDownloadFileRestartsWhenResumeIsRejected(t *testing.T) {
+	data := bytes.Repeat([]byte("strigo-restart-"), 4096)
+
+	for name, rejection := range map[string]func(w http.ResponseWriter){
+		"416": func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+		},
+		"mismatched 206": func(w http.ResponseWriter) {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(data)-1, len(data)))
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write(data)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var requests, rangeRequests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				if r.Header.Get("Range") != "" {
+					atomic.AddInt32(&rangeRequests, 1)
+					rejection(w)
+					return
+				}
+				_, _ = w.Write(data)
+			}))
+			t.Cleanup(server.Close)
+
+			dest := filepath.Join(t.TempDir(), "jdk.tar.gz")
+			url := server.URL + "/jdk.tar.gz"
+			writePartial(t, dest, data[:100], `{"url": "`+url+`", "etag": "\"1\"", "accept_ranges": true}`)
+
+			// No retries: the restart does not count as one
+			_, err := network.NewClientWithSettings("", "", noRetrySettings()).DownloadFile(url, dest, "")
+			require.NoError(t, err)
+			assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+			assert.Equal(t, int32(1), atomic.LoadInt32(&rangeRequests))
+
+			content, err := os.ReadFile(dest)
+			require.NoError(t, err)
+			assert.Equal(t, data, content)
+		})
+	}
+}
+
+func TestDownloadFileStopsWhenCancelled(t *testing.T) {
+	// The server sends the first bytes and then stalls until the client gives up
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1048576")
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	client := network.NewClientWithSettings("", "", fastRetrySettings(3))
+	client.SetContext(ctx)
+
+	start := time.Now()
+	_, err := client.DownloadFile(server.URL+"/jdk.tar.gz", filepath.Join(t.TempDir(), "jdk.tar.gz"), "")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestClientRetriesTransientStatus(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Header().Set("Content-Length", "42")
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	size, err := network.NewClientWithSettings("", "", fastRetrySettings(3)).GetFileSize(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), size)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestClientGivesUpAfterMaxRetries(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := network.NewClientWithSettings("", "", fastRetrySettings(2)).GetFileSize(server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "502")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestClientDoesNotRetryClientErrors(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := network.NewClientWithSettings("", "", fastRetrySettings(3)).GetFileSize(server.URL)
+	require.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestClientIdleTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1024")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		// Stall without sending the body
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	settings := noRetrySettings()
+	settings.IdleTimeout = 100 * time.Millisecond
+
+	start := time.Now()
+	_, err := network.NewClientWithSettings("", "", settings).DownloadFile(server.URL+"/jdk.tar.gz", filepath.Join(t.TempDir(), "jdk.tar.gz"), "")
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestSettingsFromRegistry(t *testing.T) {
+	retries := 0
+	settings, err := network.SettingsFromRegistry(config.Registry{
+		ConnectTimeout: "5s",
+		IdleTimeout:    "2m",
+		MaxRetries:     &retries,
+		RetryBackoff:   "250ms",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, settings.ConnectTimeout)
+	assert.Equal(t, 2*time.Minute, settings.IdleTimeout)
+	assert.Equal(t, network.DefaultSettings().ResponseHeaderTimeout, settings.ResponseHeaderTimeout)
+	assert.Equal(t, 0, settings.Retry.MaxRetries)
+	assert.Equal(t, 250*time.Millisecond, settings.Retry.InitialBackoff)
+
+	_, err = network.SettingsFromRegistry(config.Registry{ConnectTimeout: "soon"})
+	assert.Error(t, err)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := network.RetryPolicy{MaxRetries: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt := 1; attempt <= 5; attempt++ {
+		expected := 100 * time.Millisecond << uint(attempt-1)
+		if expected > time.Second {
+			expected = time.Second
+		}
+		delay := policy.Backoff(attempt, 0)
+		assert.GreaterOrEqual(t, delay, expected/2, "attempt %d", attempt)
+		assert.LessOrEqual(t, delay, expected, "attempt %d", attempt)
+	}
+
+	// Retry-After wins, but is capped by MaxBackoff
+	assert.Equal(t, 500*time.Millisecond, policy.Backoff(1, 500*time.Millisecond))
+	assert.Equal(t, time.Second, policy.Backoff(1, time.Minute))
+}
@@ -264,21 +264,19 @@ func TestNexusClientMixedVersionFormats(t *testing.T) {
 
 // TestNexusClientNetworkTimeout tests handling of network timeouts
 func TestNexusClientNetworkTimeout(t *testing.T) {
-	// Note: This test might take some time depending on default HTTP client timeout
-	// Skip if running in CI or add a timeout to the test context
-	t.Skip("Skipping timeout test - requires HTTP client timeout configuration")
-
 	// Create a server that never responds
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Sleep longer than the client timeout (if configured)
-		// For now, just test that slow servers don't hang forever
-		select {}
+		// Sleep longer than the client timeout, until the client gives up
+		<-r.Context().Done()
 	}))
 	defer server.Close()
 
+	maxRetries := 0
 	registry := config.Registry{
-		Type:   "nexus",
-		APIURL: server.URL + "/service/rest/v1/assets?repository={repository}",
+		Type:                  "nexus",
+		APIURL:                server.URL + "/service/rest/v1/assets?repository={repository}",
+		ResponseHeaderTimeout: "200ms",
+		MaxRetries:            &maxRetries,
 	}
 
 	repo := config.SDKRepository{
@@ -292,3 +290,151 @@ func TestNexusClientNetworkTimeout(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "timeout")
 }
+
+// TestNexusClientSearch tests that search filters server-side and keeps pagination
+func TestNexusClientSearch(t *testing.T) {
+	var listed bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/service/rest/v1/search/assets":
+			assert.Equal(t, "raw", r.URL.Query().Get("repository"))
+			assert.Equal(t, "jdk/adoptium/temurin/*", r.URL.Query().Get("name"))
+			if r.URL.Query().Get("continuationToken") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"items": []mockNexusItem{{
+						DownloadURL: "http://nexus.example.com/repository/raw/jdk/adoptium/temurin/OpenJDK17U-jdk_x64_linux_hotspot_17.0.15_6.tar.gz",
+						Path:        "/jdk/adoptium/temurin/OpenJDK17U-jdk_x64_linux_hotspot_17.0.15_6.tar.gz",
+					}},
+					"continuationToken": "page2",
+				})
+				return
+			}
+			assert.Equal(t, "page2", r.URL.Query().Get("continuationToken"))
+			_ = json.NewEncoder(w).Encode(mockNexusResponse{Items: []mockNexusItem{{
+				DownloadURL: "http://nexus.example.com/repository/raw/jdk/adoptium/temurin/OpenJDK21U-jdk_x64_linux_hotspot_21.0.9_10.tar.gz",
+				Path:        "/jdk/adoptium/temurin/OpenJDK21U-jdk_x64_linux_hotspot_21.0.9_10.tar.gz",
+			}}})
+		default:
+			listed = true
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	search := true
+	registry := config.Registry{
+		Type:   "nexus",
+		APIURL: server.URL + "/service/rest/v1/assets?repository={repository}",
+		Search: &search,
+	}
+	repo := config.SDKRepository{Type: "jdk", Registry: "nexus", Repository: "raw", Path: "jdk/adoptium/temurin"}
+
+	assets, err := repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml")
+	require.NoError(t, err)
+	assert.Len(t, assets, 2)
+	assert.False(t, listed, "repository should not be listed when search succeeds")
+}
+
+// TestNexusClientSearchFallback tests the fallback to listing when search is unavailable
+func TestNexusClientSearchFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/service/rest/v1/assets" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(mockNexusResponse{Items: []mockNexusItem{{
+			DownloadURL: "http://nexus.example.com/repository/raw/jdk/adoptium/temurin/OpenJDK17U-jdk_x64_linux_hotspot_17.0.15_6.tar.gz",
+			Path:        "/jdk/adoptium/temurin/OpenJDK17U-jdk_x64_linux_hotspot_17.0.15_6.tar.gz",
+		}}})
+	}))
+	defer server.Close()
+
+	search := true
+	registry := config.Registry{
+		Type:   "nexus",
+		APIURL: server.URL + "/service/rest/v1/assets?repository={repository}",
+		Search: &search,
+	}
+	repo := config.SDKRepository{Type: "jdk", Registry: "nexus", Repository: "raw", Path: "jdk/adoptium/temurin"}
+
+	assets, err := repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml")
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	assert.Equal(t, "17.0.15_6", assets[0].Version)
+}
+
+// TestNexusClientAssetMetadata tests that size, modification time and content type are kept
+func TestNexusClientAssetMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"items": []map[string]interface{}{{
+				"downloadUrl":  "http://nexus.example.com/repository/raw/jdk/adoptium/temurin/OpenJDK17U-jdk_x64_linux_hotspot_17.0.15_6.tar.gz",
+				"path":         "/jdk/adoptium/temurin/OpenJDK17U-jdk_x64_linux_hotspot_17.0.15_6.tar.gz",
+				"fileSize":     190000000,
+				"lastModified": "2025-04-16T08:30:00.000+00:00",
+				"contentType":  "application/x-gzip",
+				"checksum":     map[string]string{"sha256": "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+			}},
+		})
+	}))
+	defer server.Close()
+
+	registry := config.Registry{Type: "nexus", APIURL: server.URL + "/service/rest/v1/assets?repository={repository}"}
+	repo := config.SDKRepository{Type: "jdk", Registry: "nexus", Repository: "raw", Path: "jdk/adoptium/temurin"}
+
+	assets, err := repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml")
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	assert.Equal(t, int64(190000000), assets[0].Size)
+	assert.Equal(t, "2025-04-16T08:30:00.000+00:00", assets[0].LastModified)
+	assert.Equal(t, "application/x-gzip", assets[0].ContentType)
+	assert.Equal(t, "sha256", assets[0].ChecksumAlgorithm)
+}
+
+// TestNexusClientVariants tests that CRaC, FX and musl builds of a version are kept apart
+func TestNexusClientVariants(t *testing.T) {
+	files := []string{
+		"zulu21.38.21-ca-jdk21.0.5-linux_x64.tar.gz",
+		"zulu21.38.21-ca-crac-jdk21.0.5-linux_x64.tar.gz",
+		"zulu21.38.21-ca-fx-jdk21.0.5-linux_x64.tar.gz",
+		"zulu21.38.21-ca-jdk21.0.5-linux_musl_x64.tar.gz",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response mockNexusResponse
+		for _, name := range files {
+			response.Items = append(response.Items, mockNexusItem{
+				DownloadURL: "http://nexus.example.com/repository/raw/jdk/zulu/" + name,
+				Path:        "/jdk/zulu/" + name,
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	registry := config.Registry{
+		Type:   "nexus",
+		APIURL: server.URL + "/service/rest/v1/assets?repository={repository}",
+	}
+	repo := config.SDKRepository{Type: "jdk", Registry: "nexus", Repository: "raw", Path: "jdk/zulu"}
+
+	assets, err := repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml", repository.Platform{OS: "linux", Arch: "amd64"})
+	require.NoError(t, err)
+	require.Len(t, assets, 4)
+
+	byName := make(map[string]repository.SDKAsset)
+	for _, asset := range assets {
+		assert.Equal(t, "21.0.5", asset.Version)
+		assert.Equal(t, "21.38.21", asset.Build)
+		byName[asset.Name()] = asset
+	}
+	// The plain build comes first
+	assert.Equal(t, "21.0.5", assets[0].Name())
+	assert.Contains(t, byName["21.0.5-crac"].DownloadUrl, "-ca-crac-")
+	assert.Contains(t, byName["21.0.5-fx"].DownloadUrl, "-ca-fx-")
+	assert.Contains(t, byName["21.0.5-musl"].DownloadUrl, "linux_musl_x64")
+	assert.Equal(t, "linux", byName["21.0.5-musl"].OS)
+}
@@ -0,0 +1,117 @@
+package unit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strigo/config"
+	"strigo/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockOCI serves the sdks/jdk/temurin repository behind a token challenge. Tag 21.0.5_11 is a
+// single-layer ORAS manifest, tag 17.0.13_11 an image index pointing to per-platform manifests.
+func mockOCI(t *testing.T, blob []byte) *httptest.Server {
+	t.Helper()
+
+	sum := sha256.Sum256(blob)
+	blobDigest := "sha256:" + hex.EncodeToString(sum[:])
+	layerManifest := map[string]interface{}{
+		"mediaType": "application/vnd.oci.image.manifest.v1+json",
+		"layers": []map[string]interface{}{{
+			"mediaType":   "application/vnd.oci.image.layer.v1.tar+gzip",
+			"digest":      blobDigest,
+			"size":        len(blob),
+			"annotations": map[string]string{"org.opencontainers.image.title": "OpenJDK21U-jdk.tar.gz"},
+		}},
+	}
+	index := map[string]interface{}{
+		"mediaType": "application/vnd.oci.image.index.v1+json",
+		"manifests": []map[string]interface{}{
+			{"digest": "sha256:other", "platform": map[string]string{"os": "plan9", "architecture": "mips"}},
+			{"digest": "sha256:host", "platform": map[string]string{"os": runtime.GOOS, "architecture": runtime.GOARCH}},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			assert.Equal(t, "registry.test", r.URL.Query().Get("service"))
+			assert.Equal(t, "repository:sdks/jdk/temurin:pull", r.URL.Query().Get("scope"))
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "robot" || pass != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "tok"})
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="http://`+r.Host+`/token",service="registry.test",scope="repository:sdks/jdk/temurin:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/sdks/jdk/temurin/tags/list":
+			if r.URL.Query().Get("last") == "" {
+				w.Header().Set("Link", `</v2/sdks/jdk/temurin/tags/list?last=17.0.13_11&n=2>; rel="next"`)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"tags": []string{"latest", "17.0.13_11"}})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"tags": []string{"21.0.5_11"}})
+		case "/v2/sdks/jdk/temurin/manifests/21.0.5_11", "/v2/sdks/jdk/temurin/manifests/sha256:host":
+			assert.Contains(t, r.Header.Get("Accept"), "application/vnd.oci.image.manifest.v1+json")
+			_ = json.NewEncoder(w).Encode(layerManifest)
+		case "/v2/sdks/jdk/temurin/manifests/17.0.13_11":
+			_ = json.NewEncoder(w).Encode(index)
+		case "/v2/sdks/jdk/temurin/blobs/" + blobDigest:
+			_, _ = w.Write(blob)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func ociRepo() config.SDKRepository {
+	return config.SDKRepository{Type: "jdk", Registry: "harbor", Repository: "sdks", Path: "jdk/temurin"}
+}
+
+func TestOCIClientWithMockServer(t *testing.T) {
+	blob := []byte("fake jdk archive")
+	server := mockOCI(t, blob)
+	registry := config.Registry{Type: "oci", APIURL: server.URL, Username: "robot", Password: "secret"}
+
+	assets, err := repository.FetchAvailableVersions(ociRepo(), registry, "", true)
+	require.NoError(t, err)
+	require.Len(t, assets, 2)
+	assert.Equal(t, "21.0.5_11", assets[0].Version)
+	assert.Equal(t, "17.0.13_11", assets[1].Version)
+	assert.Empty(t, assets[0].DownloadUrl)
+
+	sum := sha256.Sum256(blob)
+	for _, asset := range assets {
+		require.NoError(t, repository.ResolveManifest(&asset, registry), asset.Version)
+		assert.Equal(t, server.URL+"/v2/sdks/jdk/temurin/blobs/sha256:"+hex.EncodeToString(sum[:]), asset.DownloadUrl)
+		assert.Equal(t, "sha256", asset.ChecksumAlgorithm)
+		assert.Equal(t, hex.EncodeToString(sum[:]), asset.Checksum)
+		assert.Equal(t, int64(len(blob)), asset.Size)
+		assert.Equal(t, "tok", asset.BearerToken)
+	}
+}
+
+func TestOCIClientBadCredentials(t *testing.T) {
+	server := mockOCI(t, []byte("x"))
+	registry := config.Registry{Type: "oci", APIURL: server.URL, Username: "robot", Password: "wrong"}
+
+	_, err := repository.FetchAvailableVersions(ociRepo(), registry, "", true)
+	assert.ErrorContains(t, err, "token endpoint returned 401")
+}
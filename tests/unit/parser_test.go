@@ -106,6 +106,34 @@ func TestExtractVersionZulu(t *testing.T) {
 	assert.Equal(t, "11.0.24", ver)
 }
 
+func TestMatchByTypeStructuredFields(t *testing.T) {
+	parser, err := version.NewParser("../../strigo-patterns.toml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		path    string
+		version string
+		build   string
+		variant string
+		os      string
+	}{
+		{"/jdk/zulu/zulu21.38.21-ca-jdk21.0.5-linux_x64.tar.gz", "21.0.5", "21.38.21", "", "linux"},
+		{"/jdk/zulu/zulu17.60.17-ca-crac-jdk17.0.16-linux_x64.tar.gz", "17.0.16", "17.60.17", "crac", "linux"},
+		{"/jdk/zulu/ZULU21.44.17-CA-FX-JDK21.0.8-macosx_aarch64.tar.gz", "21.0.8", "21.44.17", "fx", "macosx"},
+		{"/jdk/zulu/zulu8.82.0.21-ca-jdk8.0.432-linux_musl_x64.tar.gz", "8.0.432", "8.82.0.21", "musl", "linux"},
+	}
+
+	for _, tt := range tests {
+		m, err := parser.MatchByType(tt.path, "jdk")
+		require.NoError(t, err, tt.path)
+		assert.Equal(t, tt.version, m.Version, tt.path)
+		assert.Equal(t, tt.build, m.Build, tt.path)
+		assert.Equal(t, tt.variant, m.Variant, tt.path)
+		assert.Equal(t, tt.os, m.OS, tt.path)
+		assert.Equal(t, "zulu", m.Pattern, tt.path)
+	}
+}
+
 func TestExtractVersionNodeJS(t *testing.T) {
 	parser, err := version.NewParser("../../strigo-patterns.toml")
 	require.NoError(t, err)
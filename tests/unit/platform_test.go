@@ -0,0 +1,164 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strigo/config"
+	"strigo/repository"
+	"strigo/repository/version"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePlatformNames(t *testing.T) {
+	for name, expected := range map[string]string{"mac": "darwin", "macosx": "darwin", "osx": "darwin", "win": "windows", "alpine-linux": "linux", "Linux": "linux"} {
+		assert.Equal(t, expected, repository.NormalizeOS(name), name)
+	}
+	for name, expected := range map[string]string{"x64": "amd64", "x86_64": "amd64", "aarch64": "arm64", "i686": "386", "armv7l": "arm", "s390x": "s390x"} {
+		assert.Equal(t, expected, repository.NormalizeArch(name), name)
+	}
+}
+
+func TestNormalizeArch(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"x64", "amd64"},
+		{"x86-64", "amd64"},
+		{"AMD64", "amd64"},
+		{"aarch64", "arm64"},
+		{"x86", "386"},
+		{"x86-32", "386"},
+		{"x32", "386"},
+		{"i386", "386"},
+		{"armv6l", "arm"},
+		{"armv7", "arm"},
+		{"armhf", "arm"},
+		{"ppc64el", "ppc64le"},
+		{"riscv64", "riscv64"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, repository.NormalizeArch(tt.name), tt.name)
+	}
+}
+
+func TestPlatformMatches(t *testing.T) {
+	platform := repository.Platform{OS: "linux", Arch: "arm64"}
+
+	assert.True(t, platform.Matches(repository.SDKAsset{OS: "linux", Arch: "arm64"}))
+	assert.False(t, platform.Matches(repository.SDKAsset{OS: "linux", Arch: "amd64"}))
+	assert.False(t, platform.Matches(repository.SDKAsset{OS: "darwin", Arch: "arm64"}))
+	// Files without platform run anywhere
+	assert.True(t, platform.Matches(repository.SDKAsset{}))
+	assert.True(t, platform.Matches(repository.SDKAsset{OS: "linux"}))
+	assert.Equal(t, "linux/arm64", platform.String())
+}
+
+func TestParserMatchPlatform(t *testing.T) {
+	parser, err := version.NewParser("../../strigo-patterns.toml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		path    string
+		version string
+		os      string
+		arch    string
+	}{
+		{"/jdk/temurin/OpenJDK21U-jdk_aarch64_mac_hotspot_21.0.5_11.tar.gz", "21.0.5_11", "mac", "aarch64"},
+		{"/jdk/temurin/OpenJDK17U-jdk_x64_alpine-linux_hotspot_17.0.13_11.tar.gz", "17.0.13_11", "alpine-linux", "x64"},
+		{"/jdk/corretto/amazon-corretto-21.0.5.11.1-linux-aarch64.tar.gz", "21.0.5.11.1", "linux", "aarch64"},
+		{"/jdk/zulu/zulu21.38.21-ca-jdk21.0.5-macosx_aarch64.tar.gz", "21.0.5", "macosx", "aarch64"},
+		{"/node/node-v22.13.1-darwin-arm64.tar.gz", "22.13.1", "darwin", "arm64"},
+		// No platform in the name
+		{"/jdk/temurin/jdk-11.0.26_4.tar.gz", "11.0.26_4", "", ""},
+	}
+
+	for _, tt := range tests {
+		sdkType := "jdk"
+		if filepath.Dir(tt.path) == "/node" {
+			sdkType = "node"
+		}
+		m, err := parser.MatchByType(tt.path, sdkType)
+		require.NoError(t, err, tt.path)
+		assert.Equal(t, tt.version, m.Version, tt.path)
+		assert.Equal(t, tt.os, m.OS, tt.path)
+		assert.Equal(t, tt.arch, m.Arch, tt.path)
+	}
+}
+
+func TestParserPatternPlatformFields(t *testing.T) {
+	// Named version group and platform fixed by the pattern set
+	parser, err := version.NewParserWithCustomPatterns("../../strigo-patterns.toml", []version.Pattern{{
+		Name:     "inhouse-arm",
+		Type:     "jdk",
+		OS:       "linux",
+		Arch:     "aarch64",
+		Patterns: []string{`inhouse-jdk-(?P<version>\d+\.\d+\.\d+)-arm\.tar\.gz`},
+	}})
+	require.NoError(t, err)
+
+	m, err := parser.MatchByType("/jdk/inhouse/inhouse-jdk-21.0.5-arm.tar.gz", "jdk")
+	require.NoError(t, err)
+	assert.Equal(t, "21.0.5", m.Version)
+	assert.Equal(t, "linux", m.OS)
+	assert.Equal(t, "aarch64", m.Arch)
+	assert.Equal(t, "inhouse-arm", m.Pattern)
+}
+
+func TestFetchAvailableVersionsForPlatform(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "jdk", "temurin", "21")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	for _, name := range []string{
+		"OpenJDK21U-jdk_x64_linux_hotspot_21.0.5_11.tar.gz",
+		"OpenJDK21U-jdk_aarch64_linux_hotspot_21.0.5_11.tar.gz",
+		"OpenJDK21U-jdk_aarch64_mac_hotspot_21.0.5_11.tar.gz",
+		"OpenJDK21U-jdk_x64_linux_hotspot_21.0.4_7.tar.gz",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0644))
+	}
+
+	repo := config.SDKRepository{Type: "jdk", Path: "jdk/temurin"}
+	registry := config.Registry{Type: "filesystem", APIURL: root}
+
+	assets, err := repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml", repository.Platform{OS: "linux", Arch: "arm64"})
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	assert.Equal(t, "21.0.5_11", assets[0].Version)
+	assert.Equal(t, "linux", assets[0].OS)
+	assert.Equal(t, "arm64", assets[0].Arch)
+	assert.Contains(t, assets[0].DownloadUrl, "jdk_aarch64_linux_hotspot")
+
+	assets, err = repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml", repository.Platform{OS: "linux", Arch: "amd64"})
+	require.NoError(t, err)
+	require.Len(t, assets, 2)
+	assert.Contains(t, assets[0].DownloadUrl, "jdk_x64_linux_hotspot_21.0.5_11")
+
+	_, err = repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml", repository.Platform{OS: "windows", Arch: "amd64"})
+	assert.ErrorContains(t, err, "no versions found for jdk/temurin on windows/amd64")
+}
+
+func TestFetchAvailableVersionsForPlatformWithoutPath(t *testing.T) {
+	// The whole Nexus repository is listed, its only JDK is built for linux/amd64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []map[string]string{{
+			"downloadUrl": "http://" + r.Host + "/repository/raw/OpenJDK21U-jdk_x64_linux_hotspot_21.0.5_11.tar.gz",
+			"path":        "/OpenJDK21U-jdk_x64_linux_hotspot_21.0.5_11.tar.gz",
+		}}})
+	}))
+	defer server.Close()
+
+	repo := config.SDKRepository{Type: "jdk", Repository: "raw"}
+	registry := config.Registry{Type: "nexus", APIURL: server.URL + "/service/rest/v1/assets?repository={repository}"}
+
+	_, err := repository.FetchAvailableVersions(repo, registry, "", true, "../../strigo-patterns.toml", repository.Platform{OS: "linux", Arch: "arm64"})
+	assert.EqualError(t, err, "no versions found for raw on linux/arm64")
+}
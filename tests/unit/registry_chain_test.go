@@ -0,0 +1,177 @@
+package unit
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strigo/config"
+	"strigo/downloader"
+	"strigo/downloader/core"
+	"strigo/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockNexusWith serves a Nexus listing of Temurin archives for the given versions
+func mockNexusWith(t *testing.T, versions ...string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		items := ""
+		for i, v := range versions {
+			if i > 0 {
+				items += ","
+			}
+			path := fmt.Sprintf("/jdk/temurin/OpenJDK-jdk_x64_linux_hotspot_%s.tar.gz", v)
+			items += fmt.Sprintf(`{"path":%q,"downloadUrl":"http://%s/repository/raw%s"}`, path, r.Host, path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"items":[%s]}`, items)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func nexusRegistry(name string, server *httptest.Server) config.NamedRegistry {
+	return config.NamedRegistry{
+		Name:     name,
+		Registry: config.Registry{Type: "nexus", APIURL: server.URL + "/service/rest/v1/assets?repository={repository}"},
+	}
+}
+
+func TestFetchFromRegistriesMergesMirrors(t *testing.T) {
+	eu := mockNexusWith(t, "17.0.15_6", "21.0.9_10")
+	us := mockNexusWith(t, "21.0.9_10", "21.0.8_9")
+
+	repo := config.SDKRepository{Type: "jdk", Repository: "raw", Path: "jdk/temurin", Registries: []string{"nexus-eu", "nexus-us"}}
+	registries := []config.NamedRegistry{nexusRegistry("nexus-eu", eu), nexusRegistry("nexus-us", us)}
+
+	assets, err := repository.FetchFromRegistries(repo, registries, "", true, "../../strigo-patterns.toml")
+	require.NoError(t, err)
+	require.Len(t, assets, 3)
+
+	byVersion := make(map[string]repository.SDKAsset)
+	for _, asset := range assets {
+		byVersion[asset.Version] = asset
+	}
+
+	// The highest-priority registry supplies the version, the others are its mirrors
+	jdk21 := byVersion["21.0.9_10"]
+	assert.Equal(t, "nexus-eu", jdk21.Registry)
+	require.Len(t, jdk21.Mirrors, 1)
+	assert.Equal(t, "nexus-us", jdk21.Mirrors[0].Registry)
+	assert.Contains(t, jdk21.Mirrors[0].DownloadUrl, us.Listener.Addr().String())
+
+	assert.Equal(t, "nexus-us", byVersion["21.0.8_9"].Registry)
+	assert.Empty(t, byVersion["17.0.15_6"].Mirrors)
+}
+
+func TestFetchFromRegistriesSkipsFailingRegistry(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer down.Close()
+	us := mockNexusWith(t, "21.0.9_10")
+
+	repo := config.SDKRepository{Type: "jdk", Repository: "raw", Path: "jdk/temurin"}
+	assets, err := repository.FetchFromRegistries(repo, []config.NamedRegistry{nexusRegistry("nexus-eu", down), nexusRegistry("nexus-us", us)}, "", true, "../../strigo-patterns.toml")
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	assert.Equal(t, "nexus-us", assets[0].Registry)
+
+	_, err = repository.FetchFromRegistries(repo, []config.NamedRegistry{nexusRegistry("nexus-eu", down)}, "", true, "../../strigo-patterns.toml")
+	assert.ErrorContains(t, err, "nexus API returned 404")
+}
+
+func TestIsMirrorFailure(t *testing.T) {
+	archive := buildTarGz(t, map[string]string{"jdk-17/release": "JAVA_VERSION=17"})
+	server := serveArchive(t, "jdk.tar.gz", archive)
+
+	tmpDir := t.TempDir()
+	opts := core.DownloadOptions{
+		DownloadURL:       server.URL + "/jdk.tar.gz",
+		CacheDir:          filepath.Join(tmpDir, "cache"),
+		InstallPath:       filepath.Join(tmpDir, "sdks", "17"),
+		SDKType:           "jdk",
+		Distribution:      "temurin",
+		Version:           "17",
+		Checksum:          "0000000000000000000000000000000000000000",
+		ChecksumAlgorithm: "sha1",
+	}
+	require.NoError(t, os.MkdirAll(opts.CacheDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(opts.InstallPath), 0755))
+
+	_, err := downloader.NewManager().DownloadAndExtract(opts)
+	assert.True(t, downloader.IsMirrorFailure(err), "checksum mismatch")
+
+	// Nothing listens on a closed server
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	opts.DownloadURL = closed.URL + "/jdk.tar.gz"
+	opts.Checksum = ""
+	_, err = downloader.NewManagerWithSettings("", "", noRetrySettings()).DownloadAndExtract(opts)
+	assert.True(t, downloader.IsMirrorFailure(err), "connection refused: %v", err)
+
+	assert.False(t, downloader.IsMirrorFailure(fmt.Errorf("failed to extract archive")))
+}
+
+func TestMirrorFailoverOnHeadProbeFailure(t *testing.T) {
+	archive := buildTarGz(t, map[string]string{"jdk-17/release": "JAVA_VERSION=17"})
+	mirror := serveArchive(t, "jdk.tar.gz", archive)
+	// The registry lists the file but its server is failing, starting with the size probe
+	var heads int
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			heads++
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(failing.Close)
+
+	tmpDir := t.TempDir()
+	opts := core.DownloadOptions{
+		DownloadURL:  failing.URL + "/jdk.tar.gz",
+		Size:         0,
+		CacheDir:     filepath.Join(tmpDir, "cache"),
+		InstallPath:  filepath.Join(tmpDir, "sdks", "17"),
+		SDKType:      "jdk",
+		Distribution: "temurin",
+		Version:      "17",
+	}
+	require.NoError(t, os.MkdirAll(opts.CacheDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(opts.InstallPath), 0755))
+
+	manager := downloader.NewManagerWithSettings("", "", noRetrySettings())
+	_, err := manager.DownloadAndExtract(opts)
+	require.Error(t, err)
+	assert.Equal(t, 1, heads)
+	assert.True(t, downloader.IsMirrorFailure(err), "503 on HEAD: %v", err)
+
+	// The install moves on to the next mirror
+	opts.DownloadURL = mirror.URL + "/jdk.tar.gz"
+	_, err = manager.DownloadAndExtract(opts)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(opts.InstallPath, "jdk-17", "release"))
+}
+
+func TestConfigRegistryChainValidation(t *testing.T) {
+	cfg := config.Config{
+		Registries: map[string]config.Registry{"nexus-eu": {Type: "nexus"}},
+		SDKRepositories: map[string]config.SDKRepository{
+			"temurin": {Type: "jdk", Registries: []string{"nexus-eu", "nexus-us"}},
+		},
+	}
+	assert.ErrorContains(t, cfg.Validate(), "registry nexus-us not found")
+
+	cfg.Registries["nexus-us"] = config.Registry{Type: "nexus"}
+	require.NoError(t, cfg.Validate())
+	registries, err := cfg.RegistriesFor(cfg.SDKRepositories["temurin"])
+	require.NoError(t, err)
+	require.Len(t, registries, 2)
+	assert.Equal(t, "nexus-eu", registries[0].Name)
+	assert.Equal(t, "nexus-us", registries[1].Name)
+}
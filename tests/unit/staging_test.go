@@ -0,0 +1,88 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strigo/downloader"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStagingCommit(t *testing.T) {
+	installPath := filepath.Join(t.TempDir(), "jdks", "temurin", "17.0.13_11")
+
+	staging, err := downloader.NewStaging(installPath)
+	require.NoError(t, err)
+	assert.True(t, downloader.IsStagingDir(filepath.Base(staging.Path())))
+	assert.Equal(t, filepath.Dir(installPath), filepath.Dir(staging.Path()), "staging must be a sibling of the install path")
+
+	require.NoError(t, os.WriteFile(filepath.Join(staging.Path(), "release"), []byte("JAVA_VERSION=17"), 0644))
+	assert.NoDirExists(t, installPath, "nothing is visible before commit")
+
+	require.NoError(t, staging.Commit())
+	assert.FileExists(t, filepath.Join(installPath, "release"))
+	assert.NoDirExists(t, staging.Path())
+
+	// Staging marker is not shipped with the installation
+	entries, err := os.ReadDir(installPath)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestStagingAbort(t *testing.T) {
+	installPath := filepath.Join(t.TempDir(), "17.0.13_11")
+
+	staging, err := downloader.NewStaging(installPath)
+	require.NoError(t, err)
+	staging.Abort()
+
+	assert.NoDirExists(t, staging.Path())
+	assert.NoDirExists(t, installPath)
+}
+
+func TestStagingCommitExistingInstall(t *testing.T) {
+	installPath := filepath.Join(t.TempDir(), "17.0.13_11")
+	require.NoError(t, os.MkdirAll(filepath.Join(installPath, "jdk"), 0755))
+
+	staging, err := downloader.NewStaging(installPath)
+	require.NoError(t, err)
+	defer staging.Abort()
+	require.NoError(t, os.WriteFile(filepath.Join(staging.Path(), "release"), nil, 0644))
+
+	err = staging.Commit()
+	require.Error(t, err)
+	assert.DirExists(t, filepath.Join(installPath, "jdk"), "existing installation must be untouched")
+}
+
+func TestCleanStaleStaging(t *testing.T) {
+	dir := t.TempDir()
+
+	// Left behind by a crashed process
+	stale := filepath.Join(dir, ".strigo-staging-17.0.13_11-999999")
+	require.NoError(t, os.MkdirAll(filepath.Join(stale, "jdk-17"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(stale, ".strigo-staging.pid"), []byte("2147483646"), 0644))
+
+	// Owned by a running process (the parent of the test binary)
+	active := filepath.Join(dir, ".strigo-staging-21.0.5_11-1")
+	require.NoError(t, os.MkdirAll(active, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(active, ".strigo-staging.pid"), []byte(strconv.Itoa(os.Getppid())), 0644))
+
+	// Regular installation
+	installed := filepath.Join(dir, "11.0.24_8")
+	require.NoError(t, os.MkdirAll(installed, 0755))
+
+	removed, err := downloader.CleanStaleStaging(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.NoDirExists(t, stale)
+	assert.DirExists(t, active)
+	assert.DirExists(t, installed)
+
+	// Missing directory is not an error
+	removed, err = downloader.CleanStaleStaging(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed)
+}
@@ -0,0 +1,92 @@
+package unit
+
+import (
+	"sort"
+	"strigo/repository/version"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseVersion tests the version grammar on vendor formats
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		release []int
+		pre     string
+		build   []int
+	}{
+		{name: "JEP 223 build", version: "17.0.11+9", release: []int{17, 0, 11}, build: []int{9}},
+		{name: "GraalVM dotted build", version: "17.0.11+7.1", release: []int{17, 0, 11}, build: []int{7, 1}},
+		{name: "Temurin build", version: "21.0.5_11", release: []int{21, 0, 5}, build: []int{11}},
+		{name: "Java 8 legacy", version: "8u442b06", release: []int{8, 0, 442}, build: []int{6}},
+		{name: "Java 8 legacy with dash", version: "8u442-b06", release: []int{8, 0, 442}, build: []int{6}},
+		{name: "Corretto 5-part", version: "11.0.26.4.1", release: []int{11, 0, 26, 4, 1}},
+		{name: "Zulu beta", version: "17.0.16-beta.2", release: []int{17, 0, 16}, pre: "beta.2"},
+		{name: "Early access", version: "24-ea+20", release: []int{24}, pre: "ea", build: []int{20}},
+		{name: "Early access suffix", version: "24+20-ea", release: []int{24}, pre: "ea", build: []int{20}},
+		{name: "Prefixed", version: "jdk-17.0.11", release: []int{17, 0, 11}},
+		{name: "Node.js", version: "v22.13.1", release: []int{22, 13, 1}},
+		{name: "LTS qualifier", version: "21.0.5+11-LTS", release: []int{21, 0, 5}, build: []int{11}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := version.Parse(tt.version)
+			require.NoError(t, err)
+			assert.Equal(t, tt.release, v.Release)
+			assert.Equal(t, tt.pre, v.PreRelease)
+			assert.Equal(t, tt.build, v.Build)
+			assert.Equal(t, tt.version, v.String())
+		})
+	}
+
+	for _, invalid := range []string{"", "latest", "abc"} {
+		_, err := version.Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+// TestVersionCompare tests the ordering of parsed versions
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		older, newer string
+	}{
+		{"17.0.16-beta.2", "17.0.16"},
+		{"17.0.16-beta.2", "17.0.16-beta.10"},
+		{"24-ea+20", "24+36"},
+		{"17.0.11", "17.0.11+7"},
+		{"17.0.11+7", "17.0.11+7.1"},
+		{"17.0.11+9", "17.0.11_10"},
+		{"8u432b06", "8u442-b06"},
+		{"8u442b06", "11.0.26.4.1"},
+		{"11.0.26.4.1", "11.0.26.5.1"},
+		{"21.0.9", "21.0.10"},
+	}
+
+	for _, tt := range tests {
+		older, err := version.Parse(tt.older)
+		require.NoError(t, err)
+		newer, err := version.Parse(tt.newer)
+		require.NoError(t, err)
+
+		assert.Equal(t, -1, older.Compare(newer), "%s < %s", tt.older, tt.newer)
+		assert.Equal(t, 1, newer.Compare(older), "%s > %s", tt.newer, tt.older)
+	}
+
+	// Different spellings of the same version are equal
+	a, _ := version.Parse("21.0.5+11")
+	b, _ := version.Parse("21.0.5_11")
+	assert.Equal(t, 0, a.Compare(b))
+}
+
+// TestCompareVersionsSortsMixedFormats tests sorting a mixed list of versions
+func TestCompareVersionsSortsMixedFormats(t *testing.T) {
+	versions := []string{"21.0.10+7", "8u442b06", "21.0.9_10", "17.0.16-beta.2", "17.0.16", "21.0.9"}
+	sort.Slice(versions, func(i, j int) bool {
+		return version.CompareVersions(versions[i], versions[j])
+	})
+	assert.Equal(t, []string{"8u442b06", "17.0.16-beta.2", "17.0.16", "21.0.9", "21.0.9_10", "21.0.10+7"}, versions)
+}